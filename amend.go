@@ -0,0 +1,139 @@
+package matchingo
+
+import (
+	"github.com/nikolaydubina/fpdecimal"
+)
+
+// AmendOrder changes remaining quantity and Price of resting Order. Reduced quantity keeps time priority,
+// increased quantity or changed Price re-queues Order at the back and matches it if it crosses the book.
+// STOP-MARKET Order has zero Price.
+func (ob *OrderBook) AmendOrder(orderID string, quantity, price fpdecimal.Decimal) (done *Done, err error) {
+	order := ob.GetOrder(orderID)
+	if order == nil {
+		return nil, ErrOrderNotFound
+	}
+
+	if quantity.LessThanOrEqual(fpdecimal.Zero) {
+		return nil, ErrInvalidQuantity
+	}
+
+	if order.IsStopOrder() {
+		return ob.amendStopOrder(order, quantity, price)
+	}
+
+	if price.LessThanOrEqual(fpdecimal.Zero) || (order.IsPegged() && !price.Equal(order.Price())) {
+		return nil, ErrInvalidPrice
+	}
+
+	total := order.Quantity().Add(order.Reserve())
+
+	if price.Equal(order.Price()) && quantity.LessThanOrEqual(total) {
+		ob.reduceOrder(order, total.Sub(quantity))
+
+		done = newDone(order)
+		done.Quantity = quantity
+		done.Stored = true
+		return
+	}
+
+	ob.deleteOrder(order)
+	order.price = price
+	order.quantity = quantity
+	order.reserve = fpdecimal.Zero
+	order.SetTaker()
+
+	done, err = ob.processLimitOrder(order)
+	if err != nil {
+		return
+	}
+
+	ob.processTriggered(done)
+	ob.repricePegged(done)
+
+	return
+}
+
+// reduceOrder decreases quantity of resting Order, iceberg Order loses its reserve first
+func (ob *OrderBook) reduceOrder(order *Order, quantity fpdecimal.Decimal) {
+	if order.Reserve().GreaterThanOrEqual(quantity) {
+		order.reserve = order.Reserve().Sub(quantity)
+		return
+	}
+
+	quantity = quantity.Sub(order.Reserve())
+	order.reserve = fpdecimal.Zero
+
+	if order.Side() == Buy {
+		ob.bids.Decrease(order, quantity)
+	} else {
+		ob.asks.Decrease(order, quantity)
+	}
+}
+
+func (ob *OrderBook) amendStopOrder(order *Order, quantity, price fpdecimal.Decimal) (done *Done, err error) {
+	if order.orderType == TypeStopMarket && !price.Equal(fpdecimal.Zero) ||
+		order.orderType == TypeStopLimit && price.LessThanOrEqual(fpdecimal.Zero) {
+		return nil, ErrInvalidPrice
+	}
+
+	// Stop Orders are queued by Stop Price, re-queued Order is activated after others at the same Stop Price
+	if !price.Equal(order.Price()) || quantity.GreaterThan(order.Quantity()) {
+		ob.Stop.Remove(order)
+		order.price = price
+		order.quantity = quantity
+		ob.Stop.Append(order)
+	} else {
+		order.quantity = quantity
+	}
+
+	done = newDone(order)
+	done.Stored = true
+	return
+}
+
+// Replace atomically cancels resting Order and processes new Order instead of it, OCO links of canceled Order
+// are carried over to new Order. Replace is rejected if old Order is already filled or canceled.
+func (ob *OrderBook) Replace(orderID string, order *Order) (done *Done, err error) {
+	old := ob.GetOrder(orderID)
+	if old == nil {
+		return nil, ErrOrderNotFound
+	}
+
+	if order.ID() != orderID && ob.GetOrder(order.ID()) != nil {
+		return nil, ErrOrderExists
+	}
+
+	if order.TIF() == GTD && (order.ExpireAt().IsZero() || order.IsExpired(ob.Now())) {
+		return nil, ErrInvalidExpiry
+	}
+
+	ob.cancelOrder(orderID)
+	ob.replaceOCO(old, order)
+
+	done, err = ob.process(order)
+	if err != nil {
+		return
+	}
+
+	done.appendCanceled(old)
+
+	ob.processTriggered(done)
+	ob.repricePegged(done)
+
+	return
+}
+
+// replaceOCO links new Order to OCO pair and OCO group of replaced Order
+func (ob *OrderBook) replaceOCO(old, order *Order) {
+	if order.oco == "" && old.oco != "" {
+		order.oco = old.oco
+		if partner := ob.GetOrder(old.oco); partner != nil && partner.oco == old.ID() {
+			partner.oco = order.ID()
+		}
+	}
+
+	if order.group == "" && old.group != "" {
+		order.group = old.group
+		ob.group(old.group).replace(old.ID(), order.ID())
+	}
+}
@@ -0,0 +1,35 @@
+package matchingo
+
+import (
+	"fmt"
+
+	"github.com/nikolaydubina/fpdecimal"
+)
+
+// fillBracket submits take-profit and stop-loss OCO pair for filled quantity of bracket Order,
+// exit Orders are processed when current matching is finished, if any of their IDs already exists both are canceled
+func (ob *OrderBook) fillBracket(order *Order, quantity fpdecimal.Decimal, done *Done) {
+	if !order.IsBracket() || quantity.LessThanOrEqual(fpdecimal.Zero) {
+		return
+	}
+
+	n := len(order.children)/2 + 1
+	takeProfitID := fmt.Sprintf("%s-tp-%d", order.ID(), n)
+	stopLossID := fmt.Sprintf("%s-sl-%d", order.ID(), n)
+
+	side := order.Side().opposite()
+	takeProfit := NewLimitOrder(takeProfitID, side, quantity, order.TakeProfit(), "", stopLossID)
+	stopLoss := NewStopMarketOrder(stopLossID, side, quantity, order.StopLoss(), takeProfitID)
+
+	order.children = append(order.children, takeProfitID, stopLossID)
+
+	if ob.GetOrder(takeProfitID) != nil || ob.GetOrder(stopLossID) != nil {
+		takeProfit.Cancel()
+		stopLoss.Cancel()
+		done.appendCanceled(takeProfit)
+		done.appendCanceled(stopLoss)
+		return
+	}
+
+	ob.triggered = append(ob.triggered, takeProfit, stopLoss)
+}
@@ -0,0 +1,130 @@
+package matchingo
+
+import (
+	"sort"
+
+	"github.com/nikolaydubina/fpdecimal"
+)
+
+// CancelFilter selects Orders for mass cancellation, Order matches if it satisfies all filters which are set
+type CancelFilter struct {
+	side      Side
+	hasSide   bool
+	minPrice  fpdecimal.Decimal
+	maxPrice  fpdecimal.Decimal
+	hasPrice  bool
+	orderType OrderType
+	tif       TIF
+	owner     string
+}
+
+// NewCancelFilter creates filter which matches all Orders
+func NewCancelFilter() *CancelFilter {
+	return &CancelFilter{}
+}
+
+// SetSide matches Orders of given side
+func (f *CancelFilter) SetSide(side Side) *CancelFilter {
+	f.side = side
+	f.hasSide = true
+	return f
+}
+
+// SetPriceRange matches Orders with Price (Stop Price for Stop Orders) between minPrice and maxPrice inclusively
+func (f *CancelFilter) SetPriceRange(minPrice, maxPrice fpdecimal.Decimal) *CancelFilter {
+	if minPrice.GreaterThan(maxPrice) {
+		panic(ErrInvalidPrice)
+	}
+
+	f.minPrice = minPrice
+	f.maxPrice = maxPrice
+	f.hasPrice = true
+	return f
+}
+
+// SetType matches Orders of given type (LIMIT, STOP-LIMIT, STOP-MARKET or STOP for any Stop Order)
+func (f *CancelFilter) SetType(orderType OrderType) *CancelFilter {
+	if orderType != TypeLimit && orderType != TypeStopLimit && orderType != TypeStopMarket && orderType != TypeStop {
+		panic("unrecognized order type")
+	}
+
+	f.orderType = orderType
+	return f
+}
+
+// SetTIF matches Orders with given time in force, GTC matches Orders without time in force too
+func (f *CancelFilter) SetTIF(tif TIF) *CancelFilter {
+	if tif == "" {
+		panic(ErrInvalidTif)
+	}
+
+	f.tif = tif
+	return f
+}
+
+// SetOwner matches Orders with given owner tag
+func (f *CancelFilter) SetOwner(owner string) *CancelFilter {
+	f.owner = owner
+	return f
+}
+
+// Match returns true if Order satisfies the filter
+func (f *CancelFilter) Match(order *Order) bool {
+	if f.hasSide && order.Side() != f.side {
+		return false
+	}
+
+	if f.orderType == TypeStop && !order.IsStopOrder() || f.orderType != "" && f.orderType != TypeStop && order.orderType != f.orderType {
+		return false
+	}
+
+	if f.tif != "" && order.TIF() != f.tif && !(f.tif == GTC && order.TIF() == "") {
+		return false
+	}
+
+	if f.owner != "" && order.Owner() != f.owner {
+		return false
+	}
+
+	if f.hasPrice {
+		price := order.Price()
+		if order.IsStopOrder() {
+			price = order.StopPrice()
+		}
+		if price.LessThan(f.minPrice) || price.GreaterThan(f.maxPrice) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// CancelOrders removes all Orders which match the filter from the Order book and the Stop book the same way as CancelOrder,
+// returns sorted IDs of canceled Orders including canceled OCO group members and pegged Orders, and IDs of repriced pegged Orders
+func (ob *OrderBook) CancelOrders(filter *CancelFilter) *Cancellation {
+	matched := make([]string, 0)
+	for id, order := range ob.orders {
+		if filter.Match(order) {
+			matched = append(matched, id)
+		}
+	}
+
+	sort.Strings(matched)
+
+	cancellation := newCancellation()
+	for _, id := range matched {
+		// Order can be already canceled with its OCO group
+		order := ob.cancelOrder(id)
+		if order == nil {
+			continue
+		}
+		cancellation.appendCanceled(order)
+		ob.cancelGroup(order, cancellation)
+	}
+
+	ob.repricePegged(cancellation)
+
+	sort.Strings(cancellation.Canceled)
+
+	return cancellation
+}
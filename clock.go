@@ -0,0 +1,15 @@
+package matchingo
+
+import "time"
+
+// Clock provides current time to the OrderBook
+type Clock interface {
+	Now() time.Time
+}
+
+type systemClock struct{}
+
+// Now implements Clock interface
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
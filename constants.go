@@ -5,9 +5,15 @@ type OrderType string
 
 // Different order types
 const (
-	TypeMarket    OrderType = "MARKET"
-	TypeLimit     OrderType = "LIMIT"
-	TypeStopLimit OrderType = "STOP-LIMIT"
+	TypeMarket     OrderType = "MARKET"
+	TypeLimit      OrderType = "LIMIT"
+	TypeStopLimit  OrderType = "STOP-LIMIT"
+	TypeStopMarket OrderType = "STOP-MARKET"
+
+	TypeMarketToLimit OrderType = "MARKET-TO-LIMIT"
+
+	// TypeStop matches both STOP-LIMIT and STOP-MARKET Orders in CancelFilter
+	TypeStop OrderType = "STOP"
 )
 
 // Role of the Order
@@ -37,6 +43,14 @@ func (s Side) String() string {
 	return "SELL"
 }
 
+func (s Side) opposite() Side {
+	if s == Buy {
+		return Sell
+	}
+
+	return Buy
+}
+
 // TIF of the Order
 type TIF string
 
@@ -45,4 +59,63 @@ const (
 	GTC TIF = "GTC"
 	FOK TIF = "FOK"
 	IOC TIF = "IOC"
+	GTD TIF = "GTD"
+	DAY TIF = "DAY"
+)
+
+// TrailType of the trailing Stop Order
+type TrailType string
+
+// Different trail distance types
+const (
+	TrailAbsolute TrailType = "ABSOLUTE"
+	TrailPercent  TrailType = "PERCENT"
+)
+
+// PostOnly mode of the LIMIT Order
+type PostOnly string
+
+// Different post-only modes
+const (
+	PostOnlyReject PostOnly = "REJECT"
+	PostOnlySlide  PostOnly = "SLIDE"
+)
+
+// Rounding policy of Quote to Base quantity conversion
+type Rounding string
+
+// Different rounding policies
+const (
+	RoundDown   Rounding = "DOWN"
+	RoundUp     Rounding = "UP"
+	RoundHalfUp Rounding = "HALF-UP"
+)
+
+// StopPolicy of Stop Orders which trigger condition is already met on placement
+type StopPolicy string
+
+// Different stop placement policies
+const (
+	StopActivate StopPolicy = "ACTIVATE"
+	StopReject   StopPolicy = "REJECT"
+)
+
+// PegType of the pegged Order
+type PegType string
+
+// Different reference prices of pegged Orders
+const (
+	PegPrimary  PegType = "PRIMARY"
+	PegMarket   PegType = "MARKET"
+	PegMidpoint PegType = "MIDPOINT"
+)
+
+// Trigger direction of the Stop Order
+type Trigger string
+
+// TriggerAbove fires when the last Price rises to or above Stop Price,
+// TriggerBelow fires when the last Price falls to or below Stop Price
+const (
+	TriggerAbove Trigger = "ABOVE"
+	TriggerBelow Trigger = "BELOW"
 )
@@ -18,3 +18,25 @@ func FromFloat(num float64) fpdecimal.Decimal {
 func SetDecimalFraction(precision int) {
 	fpdecimal.FractionDigits = uint8(precision)
 }
+
+// divRound divides a by b with given rounding policy
+func divRound(a, b fpdecimal.Decimal, rounding Rounding) fpdecimal.Decimal {
+	part := a.Div(b)
+	rest := a.Sub(part.Mul(b))
+	if rest.LessThanOrEqual(fpdecimal.Zero) {
+		return part
+	}
+
+	next := part.Add(fpdecimal.FromIntScaled(1))
+
+	switch rounding {
+	case RoundUp:
+		return next
+	case RoundHalfUp:
+		if rest.GreaterThanOrEqual(next.Mul(b).Sub(a)) {
+			return next
+		}
+	}
+
+	return part
+}
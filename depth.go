@@ -14,8 +14,10 @@ type Level struct {
 }
 
 type Depth struct {
-	Ask map[string]string `json:"ask"`
-	Bid map[string]string `json:"bid"`
+	Ask    map[string]string `json:"ask"`
+	Bid    map[string]string `json:"bid"`
+	AONAsk map[string]string `json:"aonAsk"`
+	AONBid map[string]string `json:"aonBid"`
 }
 
 // Depth returns Price levels and volume at Price level
@@ -23,26 +25,39 @@ func (ob *OrderBook) Depth() *Depth {
 	var level *OrderQueue
 
 	depth := &Depth{
-		Ask: map[string]string{},
-		Bid: map[string]string{},
+		Ask:    map[string]string{},
+		Bid:    map[string]string{},
+		AONAsk: map[string]string{},
+		AONBid: map[string]string{},
 	}
 
 	level = ob.asks.BestPriceQueue()
 	fmt.Println(level)
 	for level != nil {
-		depth.Ask[level.Price().String()] = level.Volume().String()
+		appendLevel(depth.Ask, depth.AONAsk, level)
 		level = ob.asks.NextLevel(level.Price())
 	}
 
 	level = ob.bids.BestPriceQueue()
 	fmt.Println(level)
 	for level != nil {
-		depth.Bid[level.Price().String()] = level.Volume().String()
+		appendLevel(depth.Bid, depth.AONBid, level)
 		level = ob.bids.NextLevel(level.Price())
 	}
 	return depth
 }
 
+// appendLevel reports all-or-none volume separately, it isn't reliably fillable liquidity
+func appendLevel(volumes, aonVolumes map[string]string, level *OrderQueue) {
+	if level.Volume().GreaterThan(fpdecimal.Zero) {
+		volumes[level.Price().String()] = level.Volume().String()
+	}
+
+	if level.AONVolume().GreaterThan(fpdecimal.Zero) {
+		aonVolumes[level.Price().String()] = level.AONVolume().String()
+	}
+}
+
 // DepthJSON returns ask/bid depth as JSON
 func (ob *OrderBook) DepthJSON() string {
 	depth := ob.Depth()
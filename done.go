@@ -2,6 +2,7 @@ package matchingo
 
 import (
 	"encoding/json"
+	"time"
 
 	"github.com/nikolaydubina/fpdecimal"
 )
@@ -12,6 +13,8 @@ type Done struct {
 	Trades    []*TradeOrder
 	Canceled  []string
 	Activated []string
+	Triggered []*Done
+	Repriced  []string
 	Stored    bool
 	Quantity  fpdecimal.Decimal
 	Left      fpdecimal.Decimal
@@ -24,6 +27,8 @@ type DoneJSON struct {
 	Trades    []TradeOrder `json:"trades"`
 	Canceled  []string     `json:"canceled"`
 	Activated []string     `json:"activated"`
+	Triggered []*Done      `json:"triggered"`
+	Repriced  []string     `json:"repriced"`
 	Left      string       `json:"left"`
 	Processed string       `json:"processed"`
 	Stored    bool         `json:"stored"`
@@ -35,7 +40,9 @@ func newDone(order *Order) *Done {
 		Trades:    make([]*TradeOrder, 0),
 		Canceled:  make([]string, 0),
 		Activated: make([]string, 0),
-		Quantity:  order.OriginalQty(),
+		Triggered: make([]*Done, 0),
+		Repriced:  make([]string, 0),
+		Quantity:  order.Quantity(),
 		Left:      fpdecimal.Zero,
 		Processed: fpdecimal.Zero,
 	}
@@ -76,6 +83,14 @@ func (d *Done) appendActivated(order *Order) {
 	d.Activated = append(d.Activated, order.ID())
 }
 
+func (d *Done) appendTriggered(done *Done) {
+	d.Triggered = append(d.Triggered, done)
+}
+
+func (d *Done) appendRepriced(order *Order) {
+	d.Repriced = append(d.Repriced, order.ID())
+}
+
 func (d *Done) setLeftQuantity(quantity *fpdecimal.Decimal) {
 	if len(d.Trades) == 0 {
 		return
@@ -94,6 +109,8 @@ func (d *Done) MarshalJSON() ([]byte, error) {
 		Trades    []TradeOrder `json:"trades"`
 		Canceled  []string     `json:"canceled"`
 		Activated []string     `json:"activated"`
+		Triggered []*Done      `json:"triggered"`
+		Repriced  []string     `json:"repriced"`
 		Left      string       `json:"left"`
 		Processed string       `json:"processed"`
 		Stored    bool         `json:"stored"`
@@ -102,6 +119,8 @@ func (d *Done) MarshalJSON() ([]byte, error) {
 		Trades:    d.tradesToSlice(),
 		Canceled:  d.Canceled,
 		Activated: d.Activated,
+		Triggered: d.Triggered,
+		Repriced:  d.Repriced,
 		Left:      d.Left.String(),
 		Processed: d.Processed.String(),
 		Stored:    d.Stored,
@@ -114,3 +133,93 @@ func (d *Done) String() string {
 	j, _ := d.MarshalJSON()
 	return string(j)
 }
+
+// Expiry structure
+type Expiry struct {
+	Time     time.Time
+	Expired  []string
+	Canceled []string
+	Repriced []string
+}
+
+func newExpiry(now time.Time) *Expiry {
+	return &Expiry{
+		Time:     now,
+		Expired:  make([]string, 0),
+		Canceled: make([]string, 0),
+		Repriced: make([]string, 0),
+	}
+}
+
+func (e *Expiry) appendExpired(order *Order) {
+	e.Expired = append(e.Expired, order.ID())
+}
+
+func (e *Expiry) appendCanceled(order *Order) {
+	e.Canceled = append(e.Canceled, order.ID())
+}
+
+func (e *Expiry) appendRepriced(order *Order) {
+	e.Repriced = append(e.Repriced, order.ID())
+}
+
+// MarshalJSON implements Marshaler interface
+func (e *Expiry) MarshalJSON() ([]byte, error) {
+	customStruct := struct {
+		Time     time.Time `json:"time"`
+		Expired  []string  `json:"expired"`
+		Canceled []string  `json:"canceled"`
+		Repriced []string  `json:"repriced"`
+	}{
+		Time:     e.Time,
+		Expired:  e.Expired,
+		Canceled: e.Canceled,
+		Repriced: e.Repriced,
+	}
+	return json.Marshal(customStruct)
+}
+
+// String implements Stringer interface
+func (e *Expiry) String() string {
+	j, _ := e.MarshalJSON()
+	return string(j)
+}
+
+// Cancellation structure
+type Cancellation struct {
+	Canceled []string
+	Repriced []string
+}
+
+func newCancellation() *Cancellation {
+	return &Cancellation{
+		Canceled: make([]string, 0),
+		Repriced: make([]string, 0),
+	}
+}
+
+func (c *Cancellation) appendCanceled(order *Order) {
+	c.Canceled = append(c.Canceled, order.ID())
+}
+
+func (c *Cancellation) appendRepriced(order *Order) {
+	c.Repriced = append(c.Repriced, order.ID())
+}
+
+// MarshalJSON implements Marshaler interface
+func (c *Cancellation) MarshalJSON() ([]byte, error) {
+	customStruct := struct {
+		Canceled []string `json:"canceled"`
+		Repriced []string `json:"repriced"`
+	}{
+		Canceled: c.Canceled,
+		Repriced: c.Repriced,
+	}
+	return json.Marshal(customStruct)
+}
+
+// String implements Stringer interface
+func (c *Cancellation) String() string {
+	j, _ := c.MarshalJSON()
+	return string(j)
+}
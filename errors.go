@@ -7,6 +7,15 @@ var (
 	ErrInvalidQuantity      = errors.New("orderbook: invalid GetOrder Quantity")
 	ErrInvalidPrice         = errors.New("orderbook: invalid GetOrder Price")
 	ErrInvalidTif           = errors.New("orderbook: invalid GetOrder time in force")
+	ErrInvalidExpiry        = errors.New("orderbook: invalid GetOrder expiry time")
+	ErrInvalidTrigger       = errors.New("orderbook: invalid GetOrder trigger direction")
+	ErrInvalidTrail         = errors.New("orderbook: invalid GetOrder trail distance")
+	ErrInvalidPeg           = errors.New("orderbook: invalid GetOrder peg")
+	ErrInvalidPostOnly      = errors.New("orderbook: invalid GetOrder post-only mode")
+	ErrInvalidProtection    = errors.New("orderbook: invalid GetOrder protection")
+	ErrInvalidGroup         = errors.New("orderbook: invalid GetOrder OCO group")
+	ErrInvalidSecondary     = errors.New("orderbook: invalid GetOrder secondary orders")
 	ErrOrderExists          = errors.New("orderbook: GetOrder already exists")
+	ErrOrderNotFound        = errors.New("orderbook: GetOrder not found")
 	ErrInsufficientQuantity = errors.New("orderbook: insufficient Volume to calculate Price")
 )
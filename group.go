@@ -0,0 +1,184 @@
+package matchingo
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/nikolaydubina/fpdecimal"
+)
+
+// OCOGroup is named One-Cancels-Other group of any size: fill of any member cancels all other members
+type OCOGroup struct {
+	name        string
+	orders      []string
+	partialFill bool
+	cancelGroup bool
+	filled      string
+}
+
+func newOCOGroup(name string) *OCOGroup {
+	return &OCOGroup{name: name}
+}
+
+// Name returns name of the group
+func (g *OCOGroup) Name() string {
+	return g.name
+}
+
+// Orders returns IDs of group members in order of their submission
+func (g *OCOGroup) Orders() []string {
+	return g.orders
+}
+
+// PartialFill returns true if any partial fill of a member cancels other members
+func (g *OCOGroup) PartialFill() bool {
+	return g.partialFill
+}
+
+// CancelGroup returns true if cancelling one member cancels the whole group
+func (g *OCOGroup) CancelGroup() bool {
+	return g.cancelGroup
+}
+
+// Filled returns ID of the member which fill canceled other members, it is empty if group isn't triggered
+func (g *OCOGroup) Filled() string {
+	return g.filled
+}
+
+// IsTriggered returns true if other members were canceled by fill of a member
+func (g *OCOGroup) IsTriggered() bool {
+	return g.filled != ""
+}
+
+func (g *OCOGroup) join(order *Order) {
+	for _, id := range g.orders {
+		if id == order.ID() {
+			return
+		}
+	}
+
+	g.orders = append(g.orders, order.ID())
+}
+
+func (g *OCOGroup) replace(oldID, newID string) {
+	for i, id := range g.orders {
+		if id == oldID {
+			g.orders[i] = newID
+		}
+	}
+
+	if g.filled == oldID {
+		g.filled = newID
+	}
+}
+
+// String implements fmt.Stringer interface
+func (g *OCOGroup) String() string {
+	return fmt.Sprintf(
+		"%s -> orders: [%s], partialFill: %t, cancelGroup: %t, filled: %s",
+		g.name,
+		strings.Join(g.orders, ", "),
+		g.partialFill,
+		g.cancelGroup,
+		g.filled,
+	)
+}
+
+// SetGroupPolicy sets policy of OCO group with given name, by default only complete fill of a member cancels
+// other members and cancelling one member doesn't affect the group
+func (ob *OrderBook) SetGroupPolicy(name string, partialFill, cancelGroup bool) {
+	group := ob.group(name)
+	group.partialFill = partialFill
+	group.cancelGroup = cancelGroup
+}
+
+// GetGroup returns OCO group with given name or nil if group not found
+func (ob *OrderBook) GetGroup(name string) *OCOGroup {
+	return ob.groups[name]
+}
+
+func (ob *OrderBook) group(name string) *OCOGroup {
+	group, ok := ob.groups[name]
+	if !ok {
+		group = newOCOGroup(name)
+		ob.groups[name] = group
+	}
+
+	return group
+}
+
+// checkGroup cancels Order if its group is already triggered, otherwise Order joins the group
+func (ob *OrderBook) checkGroup(order *Order, done *Done) bool {
+	if order.Group() == "" {
+		return false
+	}
+
+	group := ob.group(order.Group())
+	if group.IsTriggered() && group.Filled() != order.ID() {
+		order.Cancel()
+		done.appendCanceled(order)
+		return true
+	}
+
+	group.join(order)
+	return false
+}
+
+// fillGroup cancels other members of the group after fill of Order, partial fills count if group policy allows
+func (ob *OrderBook) fillGroup(order *Order, quantity fpdecimal.Decimal, done *Done) {
+	if order.Group() == "" {
+		return
+	}
+
+	group := ob.group(order.Group())
+	if group.IsTriggered() || (!group.PartialFill() && quantity.LessThan(order.Quantity().Add(order.Reserve()))) {
+		return
+	}
+
+	group.filled = order.ID()
+	for _, id := range group.Orders() {
+		if id == order.ID() {
+			continue
+		}
+
+		if canceled := ob.cancelOrder(id); canceled != nil {
+			done.appendCanceled(canceled)
+		}
+	}
+}
+
+// cancelGroup cancels other members of the group after Order cancellation if group policy allows
+func (ob *OrderBook) cancelGroup(order *Order, cancellation *Cancellation) {
+	if order.Group() == "" {
+		return
+	}
+
+	group := ob.group(order.Group())
+	if !group.CancelGroup() {
+		return
+	}
+
+	for _, id := range group.Orders() {
+		if canceled := ob.cancelOrder(id); canceled != nil {
+			cancellation.appendCanceled(canceled)
+		}
+	}
+}
+
+func (ob *OrderBook) groupsString() string {
+	names := make([]string, 0, len(ob.groups))
+	for name := range ob.groups {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	builder := strings.Builder{}
+	for _, name := range names {
+		builder.WriteString("\n")
+		builder.WriteString(ob.groups[name].String())
+	}
+
+	return builder.String()
+}
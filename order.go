@@ -2,6 +2,7 @@ package matchingo
 
 import (
 	"encoding/json"
+	"time"
 
 	"github.com/nikolaydubina/fpdecimal"
 )
@@ -51,12 +52,34 @@ type Order struct {
 	isQuote     bool
 	quantity    fpdecimal.Decimal
 	originalQty fpdecimal.Decimal
+	displayQty  fpdecimal.Decimal
+	reserve     fpdecimal.Decimal
 	price       fpdecimal.Decimal
 	canceled    bool
 	role        Role
 	stop        fpdecimal.Decimal
+	trigger     Trigger
+	trail       fpdecimal.Decimal
+	trailType   TrailType
 	tif         TIF
+	expireAt    time.Time
 	oco         string
+	group       string
+	owner       string
+	protection  fpdecimal.Decimal
+	maxPercent  fpdecimal.Decimal
+	maxLevels   int
+	postOnly    PostOnly
+	minQty      fpdecimal.Decimal
+	allOrNone   bool
+	hidden      bool
+	peg         PegType
+	pegOffset   fpdecimal.Decimal
+	pegLimit    fpdecimal.Decimal
+	takeProfit  fpdecimal.Decimal
+	stopLoss    fpdecimal.Decimal
+	children    []string
+	secondary   []*Order
 }
 
 // NewMarketOrder creates new constant object Order
@@ -96,6 +119,14 @@ func NewMarketQuoteOrder(orderID string, side Side, quantity fpdecimal.Decimal)
 	}
 }
 
+// NewMarketToLimitOrder creates new constant object Order, its remainder rests as LIMIT at the last fill Price
+func NewMarketToLimitOrder(orderID string, side Side, quantity fpdecimal.Decimal) *Order {
+	order := NewMarketOrder(orderID, side, quantity)
+	order.orderType = TypeMarketToLimit
+
+	return order
+}
+
 // NewLimitOrder creates new constant object Order
 func NewLimitOrder(orderID string, side Side, quantity, price fpdecimal.Decimal, tif TIF, oco string) *Order {
 
@@ -107,7 +138,7 @@ func NewLimitOrder(orderID string, side Side, quantity, price fpdecimal.Decimal,
 		panic(ErrInvalidPrice)
 	}
 
-	if tif != "" && tif != GTC && tif != FOK && tif != IOC {
+	if !isLimitTIF(tif) {
 		panic(ErrInvalidTif)
 	}
 
@@ -124,6 +155,86 @@ func NewLimitOrder(orderID string, side Side, quantity, price fpdecimal.Decimal,
 	}
 }
 
+// NewBracketOrder creates new LIMIT entry Order, its take-profit LIMIT and stop-loss STOP-MARKET exit Orders
+// are submitted as OCO pair for every fill of the entry Order
+func NewBracketOrder(orderID string, side Side, quantity, price, takeProfit, stopLoss fpdecimal.Decimal, tif TIF) *Order {
+	order := NewLimitOrder(orderID, side, quantity, price, tif, "")
+
+	if side == Buy && (stopLoss.LessThanOrEqual(fpdecimal.Zero) || stopLoss.GreaterThanOrEqual(price) || takeProfit.LessThanOrEqual(price)) {
+		panic(ErrInvalidPrice)
+	}
+
+	if side == Sell && (takeProfit.LessThanOrEqual(fpdecimal.Zero) || takeProfit.GreaterThanOrEqual(price) || stopLoss.LessThanOrEqual(price)) {
+		panic(ErrInvalidPrice)
+	}
+
+	order.takeProfit = takeProfit
+	order.stopLoss = stopLoss
+
+	return order
+}
+
+// NewIcebergOrder creates new constant object Order, only displayQty of quantity is visible in the order book
+func NewIcebergOrder(orderID string, side Side, quantity, displayQty, price fpdecimal.Decimal, tif TIF, oco string) *Order {
+
+	if displayQty.LessThanOrEqual(fpdecimal.Zero) || displayQty.GreaterThan(quantity) {
+		panic(ErrInvalidQuantity)
+	}
+
+	order := NewLimitOrder(orderID, side, quantity, price, tif, oco)
+	order.displayQty = displayQty
+
+	return order
+}
+
+func isLimitTIF(tif TIF) bool {
+	return tif == "" || tif == GTC || tif == FOK || tif == IOC || tif == GTD || tif == DAY
+}
+
+// NewLimitQuoteOrder creates new constant object Order, but quantity is in Quote mode
+func NewLimitQuoteOrder(orderID string, side Side, quantity, price fpdecimal.Decimal, tif TIF, oco string) *Order {
+	order := NewLimitOrder(orderID, side, quantity, price, tif, oco)
+	order.isQuote = true
+
+	return order
+}
+
+// NewPeggedOrder creates new constant object Order, its Price follows the reference Price with offset and limit cap
+func NewPeggedOrder(orderID string, side Side, quantity fpdecimal.Decimal, peg PegType, offset, limit fpdecimal.Decimal, tif TIF, oco string) *Order {
+
+	if peg != PegPrimary && peg != PegMarket && peg != PegMidpoint {
+		panic(ErrInvalidPeg)
+	}
+
+	if limit.LessThan(fpdecimal.Zero) {
+		panic(ErrInvalidPrice)
+	}
+
+	if quantity.LessThanOrEqual(fpdecimal.Zero) {
+		panic(ErrInvalidQuantity)
+	}
+
+	if !isLimitTIF(tif) {
+		panic(ErrInvalidTif)
+	}
+
+	// Price is defined by the order book on placement
+	return &Order{
+		id:          orderID,
+		orderType:   TypeLimit,
+		side:        side,
+		quantity:    quantity,
+		originalQty: quantity,
+		price:       fpdecimal.Zero,
+		canceled:    false,
+		oco:         oco,
+		tif:         tif,
+		peg:         peg,
+		pegOffset:   offset,
+		pegLimit:    limit,
+	}
+}
+
 // NewStopLimitOrder creates new constant object Order
 func NewStopLimitOrder(orderID string, side Side, quantity, price, stop fpdecimal.Decimal, oco string) *Order {
 
@@ -139,6 +250,7 @@ func NewStopLimitOrder(orderID string, side Side, quantity, price, stop fpdecima
 		id:          orderID,
 		orderType:   TypeStopLimit,
 		side:        side,
+		trigger:     stopLossTrigger(side),
 		quantity:    quantity,
 		originalQty: quantity,
 		price:       price,
@@ -148,6 +260,99 @@ func NewStopLimitOrder(orderID string, side Side, quantity, price, stop fpdecima
 	}
 }
 
+// NewTakeProfitLimitOrder creates new constant object Order, which is triggered when the market moves in favour of it:
+// BUY is triggered when Price falls to Stop Price, SELL is triggered when Price rises to Stop Price
+func NewTakeProfitLimitOrder(orderID string, side Side, quantity, price, stop fpdecimal.Decimal, oco string) *Order {
+	order := NewStopLimitOrder(orderID, side, quantity, price, stop, oco)
+	order.trigger = takeProfitTrigger(side)
+
+	return order
+}
+
+// stopLossTrigger returns trigger direction of stop-loss Order
+func stopLossTrigger(side Side) Trigger {
+	if side == Buy {
+		return TriggerAbove
+	}
+
+	return TriggerBelow
+}
+
+// takeProfitTrigger returns trigger direction of take-profit Order
+func takeProfitTrigger(side Side) Trigger {
+	if side == Buy {
+		return TriggerBelow
+	}
+
+	return TriggerAbove
+}
+
+// NewStopMarketOrder creates new constant object Order, which becomes MARKET when Stop Price is reached
+func NewStopMarketOrder(orderID string, side Side, quantity, stop fpdecimal.Decimal, oco string) *Order {
+
+	if quantity.LessThanOrEqual(fpdecimal.Zero) {
+		panic(ErrInvalidQuantity)
+	}
+
+	if stop.LessThanOrEqual(fpdecimal.Zero) {
+		panic(ErrInvalidPrice)
+	}
+
+	return &Order{
+		id:          orderID,
+		orderType:   TypeStopMarket,
+		side:        side,
+		trigger:     stopLossTrigger(side),
+		quantity:    quantity,
+		originalQty: quantity,
+		price:       fpdecimal.Zero,
+		canceled:    false,
+		stop:        stop,
+		oco:         oco,
+	}
+}
+
+// NewStopMarketQuoteOrder creates new constant object Order, but quantity is in Quote mode
+func NewStopMarketQuoteOrder(orderID string, side Side, quantity, stop fpdecimal.Decimal, oco string) *Order {
+	order := NewStopMarketOrder(orderID, side, quantity, stop, oco)
+	order.isQuote = true
+
+	return order
+}
+
+// NewTrailingStopLimitOrder creates new constant object Order, which Stop Price follows the market on trail distance
+func NewTrailingStopLimitOrder(orderID string, side Side, quantity, price, stop, trail fpdecimal.Decimal, trailType TrailType, oco string) *Order {
+	order := NewStopLimitOrder(orderID, side, quantity, price, stop, oco)
+	order.setTrail(trail, trailType)
+
+	return order
+}
+
+// NewTrailingStopMarketOrder creates new constant object Order, which Stop Price follows the market on trail distance
+func NewTrailingStopMarketOrder(orderID string, side Side, quantity, stop, trail fpdecimal.Decimal, trailType TrailType, oco string) *Order {
+	order := NewStopMarketOrder(orderID, side, quantity, stop, oco)
+	order.setTrail(trail, trailType)
+
+	return order
+}
+
+func (o *Order) setTrail(trail fpdecimal.Decimal, trailType TrailType) {
+	if trail.LessThanOrEqual(fpdecimal.Zero) {
+		panic(ErrInvalidTrail)
+	}
+
+	if trailType != TrailAbsolute && trailType != TrailPercent {
+		panic(ErrInvalidTrail)
+	}
+
+	if trailType == TrailPercent && trail.GreaterThanOrEqual(fpdecimal.FromInt(100)) {
+		panic(ErrInvalidTrail)
+	}
+
+	o.trail = trail
+	o.trailType = trailType
+}
+
 // ID returns OrderID field copy
 func (o *Order) ID() string {
 	return o.id
@@ -173,6 +378,16 @@ func (o *Order) OriginalQty() fpdecimal.Decimal {
 	return o.originalQty
 }
 
+// DisplayQty returns visible quantity of iceberg Order
+func (o *Order) DisplayQty() fpdecimal.Decimal {
+	return o.displayQty
+}
+
+// Reserve returns hidden quantity of iceberg Order
+func (o *Order) Reserve() fpdecimal.Decimal {
+	return o.reserve
+}
+
 // SetQuantity set Quantity field
 func (o *Order) SetQuantity(quantity fpdecimal.Decimal) {
 	o.quantity = quantity
@@ -193,6 +408,40 @@ func (o *Order) StopPrice() fpdecimal.Decimal {
 	return o.stop
 }
 
+// Trigger returns trigger direction of Stop Order
+func (o *Order) Trigger() Trigger {
+	return o.trigger
+}
+
+// SetTrigger sets trigger direction of Stop Order
+func (o *Order) SetTrigger(trigger Trigger) *Order {
+	if !o.IsStopOrder() || (trigger != TriggerAbove && trigger != TriggerBelow) {
+		panic(ErrInvalidTrigger)
+	}
+
+	o.trigger = trigger
+	return o
+}
+
+// IsTriggered returns true if Stop Order trigger condition is met at given Price
+func (o *Order) IsTriggered(price fpdecimal.Decimal) bool {
+	if o.trigger == TriggerAbove {
+		return price.GreaterThanOrEqual(o.stop)
+	}
+
+	return price.LessThanOrEqual(o.stop)
+}
+
+// Trail returns trail distance of trailing Stop Order
+func (o *Order) Trail() fpdecimal.Decimal {
+	return o.trail
+}
+
+// TrailType returns type of trail distance
+func (o *Order) TrailType() TrailType {
+	return o.trailType
+}
+
 // OCO returns reference ID
 func (o *Order) OCO() string {
 	return o.oco
@@ -203,6 +452,290 @@ func (o *Order) TIF() TIF {
 	return o.tif
 }
 
+// PostOnly returns post-only mode
+func (o *Order) PostOnly() PostOnly {
+	return o.postOnly
+}
+
+// SetPostOnly marks LIMIT Order as maker-only, it is rejected or slid if it would trade immediately
+func (o *Order) SetPostOnly(mode PostOnly) *Order {
+	if !o.IsLimitOrder() || (mode != PostOnlyReject && mode != PostOnlySlide) {
+		panic(ErrInvalidPostOnly)
+	}
+
+	o.postOnly = mode
+	return o
+}
+
+// IsPostOnly returns true if Order must not take liquidity
+func (o *Order) IsPostOnly() bool {
+	return o.postOnly != ""
+}
+
+// SetTIF sets time in force, STOP-LIMIT Order applies it after activation,
+// MARKET Order supports IOC and FOK, STOP-MARKET Order supports GTC, DAY and FOK (after activation) only
+func (o *Order) SetTIF(tif TIF) *Order {
+	switch {
+	case (o.IsLimitOrder() || o.orderType == TypeStopLimit) && (tif == "" || tif == GTC || tif == FOK || tif == IOC || tif == DAY):
+	case o.IsMarketOrder() && (tif == "" || tif == IOC || tif == FOK):
+	case o.orderType == TypeStopMarket && tif == FOK:
+	case o.IsStopOrder() && (tif == "" || tif == GTC || tif == DAY):
+	default:
+		panic(ErrInvalidTif)
+	}
+
+	o.tif = tif
+	o.expireAt = time.Time{}
+	return o
+}
+
+// ExpireAt returns expiry time of GTD Order
+func (o *Order) ExpireAt() time.Time {
+	return o.expireAt
+}
+
+// SetExpiry makes Order GTD, it is removed by OrderBook.ExpireOrders at expireAt
+func (o *Order) SetExpiry(expireAt time.Time) *Order {
+	if o.IsMarketOrder() || expireAt.IsZero() {
+		panic(ErrInvalidExpiry)
+	}
+
+	o.tif = GTD
+	o.expireAt = expireAt
+	return o
+}
+
+// IsExpired returns true if GTD Order is expired at given time
+func (o *Order) IsExpired(now time.Time) bool {
+	return o.tif == GTD && !o.expireAt.After(now)
+}
+
+// MinQty returns minimal execution quantity
+func (o *Order) MinQty() fpdecimal.Decimal {
+	return o.minQty
+}
+
+// SetMinQty sets minimal execution quantity of LIMIT Order
+func (o *Order) SetMinQty(quantity fpdecimal.Decimal) *Order {
+	if !o.IsLimitOrder() {
+		panic(ErrInvalidQuantity)
+	}
+
+	if quantity.LessThanOrEqual(fpdecimal.Zero) || quantity.GreaterThan(o.Quantity()) {
+		panic(ErrInvalidQuantity)
+	}
+
+	o.minQty = quantity
+	return o
+}
+
+// IsAllOrNone returns true if Order can be filled completely only
+func (o *Order) IsAllOrNone() bool {
+	return o.allOrNone
+}
+
+// SetAllOrNone marks LIMIT Order as all-or-none, it rests until it can be filled completely
+func (o *Order) SetAllOrNone() *Order {
+	if !o.IsLimitOrder() || o.IsIceberg() {
+		panic(ErrInvalidQuantity)
+	}
+
+	o.allOrNone = true
+	return o
+}
+
+// IsHidden returns true if Order is not displayed in the order book
+func (o *Order) IsHidden() bool {
+	return o.hidden
+}
+
+// SetHidden marks LIMIT Order as non-displayed, it has lower priority than displayed Orders at the same Price
+func (o *Order) SetHidden() *Order {
+	if !o.IsLimitOrder() || o.IsIceberg() {
+		panic(ErrInvalidQuantity)
+	}
+
+	o.hidden = true
+	return o
+}
+
+// IsPegged returns true if Order Price follows the reference Price
+func (o *Order) IsPegged() bool {
+	return o.peg != ""
+}
+
+// Peg returns reference Price type of pegged Order
+func (o *Order) Peg() PegType {
+	return o.peg
+}
+
+// PegOffset returns offset from the reference Price
+func (o *Order) PegOffset() fpdecimal.Decimal {
+	return o.pegOffset
+}
+
+// PegLimit returns Price cap of pegged Order, it is zero if there is no cap
+func (o *Order) PegLimit() fpdecimal.Decimal {
+	return o.pegLimit
+}
+
+// IsBracket returns true if Order submits exit Orders when it is filled
+func (o *Order) IsBracket() bool {
+	return o.takeProfit.GreaterThan(fpdecimal.Zero)
+}
+
+// TakeProfit returns Price of take-profit exit Orders of bracket Order
+func (o *Order) TakeProfit() fpdecimal.Decimal {
+	return o.takeProfit
+}
+
+// StopLoss returns Stop Price of stop-loss exit Orders of bracket Order
+func (o *Order) StopLoss() fpdecimal.Decimal {
+	return o.stopLoss
+}
+
+// Children returns IDs of exit Orders submitted by bracket Order
+func (o *Order) Children() []string {
+	return o.children
+}
+
+// ProtectionPrice returns the worst Price MARKET Order can be filled at, it is zero if there is no limit
+func (o *Order) ProtectionPrice() fpdecimal.Decimal {
+	return o.protection
+}
+
+// SetProtectionPrice sets the worst Price MARKET Order can be filled at, remainder is canceled
+func (o *Order) SetProtectionPrice(price fpdecimal.Decimal) *Order {
+	if !o.isProtectable() || price.LessThanOrEqual(fpdecimal.Zero) {
+		panic(ErrInvalidProtection)
+	}
+
+	o.protection = price
+	return o
+}
+
+// ProtectionPercent returns maximum distance in percent from the best Price at entry
+func (o *Order) ProtectionPercent() fpdecimal.Decimal {
+	return o.maxPercent
+}
+
+// SetProtectionPercent sets maximum distance in percent from the best Price at entry MARKET Order can be filled at
+func (o *Order) SetProtectionPercent(percent fpdecimal.Decimal) *Order {
+	if !o.isProtectable() || percent.LessThanOrEqual(fpdecimal.Zero) || percent.GreaterThanOrEqual(fpdecimal.FromInt(100)) {
+		panic(ErrInvalidProtection)
+	}
+
+	o.maxPercent = percent
+	return o
+}
+
+// ProtectionLevels returns maximum number of Price levels MARKET Order can sweep
+func (o *Order) ProtectionLevels() int {
+	return o.maxLevels
+}
+
+// SetProtectionLevels sets maximum number of Price levels MARKET Order can sweep
+func (o *Order) SetProtectionLevels(levels int) *Order {
+	if !o.isProtectable() || levels <= 0 {
+		panic(ErrInvalidProtection)
+	}
+
+	o.maxLevels = levels
+	return o
+}
+
+func (o *Order) isProtectable() bool {
+	return o.orderType == TypeMarket || o.orderType == TypeStopMarket
+}
+
+// protectionLimit returns the worst Price for the best Price at entry
+func (o *Order) protectionLimit(best fpdecimal.Decimal) (fpdecimal.Decimal, bool) {
+	limit, ok := o.protection, o.protection.GreaterThan(fpdecimal.Zero)
+
+	if o.maxPercent.GreaterThan(fpdecimal.Zero) {
+		distance := best.Mul(o.maxPercent).Div(fpdecimal.FromInt(100))
+		if o.side == Buy {
+			if price := best.Add(distance); !ok || price.LessThan(limit) {
+				limit, ok = price, true
+			}
+		} else {
+			if price := best.Sub(distance); !ok || price.GreaterThan(limit) {
+				limit, ok = price, true
+			}
+		}
+	}
+
+	return limit, ok
+}
+
+// Owner returns owner tag of the Order
+func (o *Order) Owner() string {
+	return o.owner
+}
+
+// SetOwner sets owner tag of the Order, it is used by mass cancellation filter
+func (o *Order) SetOwner(owner string) *Order {
+	o.owner = owner
+	return o
+}
+
+// Group returns name of OCO group of the Order
+func (o *Order) Group() string {
+	return o.group
+}
+
+// SetGroup adds Order to named OCO group, fill of any group member cancels all other members
+func (o *Order) SetGroup(name string) *Order {
+	if name == "" {
+		panic(ErrInvalidGroup)
+	}
+
+	o.group = name
+	return o
+}
+
+// Secondary returns Orders which are submitted after the first fill of the Order
+func (o *Order) Secondary() []*Order {
+	return o.secondary
+}
+
+// SetSecondary makes One-Triggers-Other order list: secondary Orders (LIMIT, STOP or OCO pair)
+// are submitted to the order book after the first fill of the Order
+func (o *Order) SetSecondary(orders ...*Order) *Order {
+	if len(orders) == 0 {
+		panic(ErrInvalidSecondary)
+	}
+
+	for _, order := range orders {
+		if order == nil || order == o || order.ID() == o.ID() {
+			panic(ErrInvalidSecondary)
+		}
+	}
+
+	o.secondary = orders
+	return o
+}
+
+// canMatch returns true if resting Order can be matched with incoming quantity
+func (o *Order) canMatch(quantity fpdecimal.Decimal) bool {
+	if o.allOrNone && quantity.LessThan(o.quantity) {
+		return false
+	}
+
+	if o.minQty.GreaterThan(fpdecimal.Zero) {
+		minQty := o.minQty
+		total := o.quantity.Add(o.reserve)
+		if total.LessThan(minQty) {
+			minQty = total
+		}
+		if quantity.LessThan(minQty) {
+			return false
+		}
+	}
+
+	return true
+}
+
 // IsCanceled returns Canceled status
 func (o *Order) IsCanceled() bool {
 	return o.canceled
@@ -219,24 +752,100 @@ func (o *Order) IsMarketOrder() bool {
 	return o.orderType == TypeMarket
 }
 
+// IsMarketToLimitOrder returns true if Order is MARKET-TO-LIMIT
+func (o *Order) IsMarketToLimitOrder() bool {
+	return o.orderType == TypeMarketToLimit
+}
+
 // IsLimitOrder returns true if Order is LIMIT
 func (o *Order) IsLimitOrder() bool {
 	return o.orderType == TypeLimit
 }
 
-// IsStopOrder returns true if Order is STOP-LIMIT
+// IsIceberg returns true if only part of the Order is visible in the order book
+func (o *Order) IsIceberg() bool {
+	return o.displayQty.GreaterThan(fpdecimal.Zero)
+}
+
+// splitIceberg moves quantity over displayQty into reserve
+func (o *Order) splitIceberg() {
+	if !o.IsIceberg() {
+		return
+	}
+
+	total := o.quantity.Add(o.reserve)
+	o.quantity = total
+	o.reserve = fpdecimal.Zero
+	if total.GreaterThan(o.displayQty) {
+		o.quantity = o.displayQty
+		o.reserve = total.Sub(o.displayQty)
+	}
+}
+
+// IsStopOrder returns true if Order is STOP-LIMIT or STOP-MARKET
 func (o *Order) IsStopOrder() bool {
-	return o.orderType == TypeStopLimit
+	return o.orderType == TypeStopLimit || o.orderType == TypeStopMarket
 }
 
-// ActivateStopOrder transforms Stop-GetOrder into Order
+// IsTrailingStop returns true if Stop Price of the Order follows the market
+func (o *Order) IsTrailingStop() bool {
+	return o.IsStopOrder() && o.trailType != ""
+}
+
+// trailingStop returns new Stop Price if market moved in the favourable direction
+func (o *Order) trailingStop(lastPrice fpdecimal.Decimal) (fpdecimal.Decimal, bool) {
+	distance := o.trail
+	if o.trailType == TrailPercent {
+		distance = lastPrice.Mul(o.trail).Div(fpdecimal.FromInt(100))
+	}
+
+	var stop fpdecimal.Decimal
+	if o.Trigger() == TriggerAbove {
+		stop = lastPrice.Add(distance)
+		if stop.GreaterThanOrEqual(o.stop) {
+			return o.stop, false
+		}
+	} else {
+		stop = lastPrice.Sub(distance)
+		if stop.LessThanOrEqual(o.stop) {
+			return o.stop, false
+		}
+	}
+
+	// LIMIT Price follows Stop Price to keep the same offset
+	if o.orderType == TypeStopLimit && o.price.Add(stop.Sub(o.stop)).LessThanOrEqual(fpdecimal.Zero) {
+		return o.stop, false
+	}
+
+	return stop, true
+}
+
+// setStopPrice moves Stop Price and LIMIT Price on the same distance
+func (o *Order) setStopPrice(stop fpdecimal.Decimal) {
+	if o.orderType == TypeStopLimit {
+		o.price = o.price.Add(stop.Sub(o.stop))
+	}
+
+	o.stop = stop
+}
+
+// ActivateStopOrder transforms Stop-GetOrder into LIMIT or MARKET Order
 func (o *Order) ActivateStopOrder() {
 
 	if !o.IsStopOrder() {
 		panic("GetOrder isn't Stop")
 	}
 
+	// time in force is kept, it is applied to activated LIMIT Order
 	o.stop = fpdecimal.Zero
+	o.trigger = ""
+	o.trail = fpdecimal.Zero
+	o.trailType = ""
+
+	if o.orderType == TypeStopMarket {
+		o.orderType = TypeMarket
+		return
+	}
 
 	o.orderType = TypeLimit
 }
@@ -1,18 +1,32 @@
 package matchingo
 
 import (
+	"sort"
 	"strings"
+	"time"
 
+	"github.com/hashicorp/go-set"
 	"github.com/nikolaydubina/fpdecimal"
 )
 
 // OrderBook implements standard matching algorithm
 type OrderBook struct {
-	orders map[string]*Order
-	asks   *OrderSide
-	bids   *OrderSide
-	Stop   *StopBook
-	OCO    map[string]struct{}
+	orders    map[string]*Order
+	asks      *OrderSide
+	bids      *OrderSide
+	triggered []*Order
+	lastPrice fpdecimal.Decimal
+	tickSize  fpdecimal.Decimal
+	rounding  Rounding
+	stop      StopPolicy
+	pegged    []*Order
+	pegBid    fpdecimal.Decimal
+	pegAsk    fpdecimal.Decimal
+	clock     Clock
+	expiring  *set.TreeSet[*Order, set.Compare[*Order]]
+	Stop      *StopBook
+	OCO       map[string]struct{}
+	groups    map[string]*OCOGroup
 }
 
 // NewOrderBook creates Orderbook object
@@ -23,9 +37,70 @@ func NewOrderBook() *OrderBook {
 		asks:   NewOrderSideAsk(),
 		Stop:   NewStopBook(),
 		OCO:    map[string]struct{}{},
+		groups: map[string]*OCOGroup{},
+		stop:   StopActivate,
+		clock:  systemClock{},
+		// GTD Orders sorted by expiry, filled and canceled Orders are removed lazily
+		expiring: set.NewTreeSet[*Order, set.Compare[*Order]](func(a *Order, b *Order) int {
+			if !a.ExpireAt().Equal(b.ExpireAt()) {
+				return a.ExpireAt().Compare(b.ExpireAt())
+			}
+			return strings.Compare(a.ID(), b.ID())
+		}),
 	}
 }
 
+// SetQuoteRounding sets rounding policy of resting Quote quantity conversion, it is RoundDown by default
+func (ob *OrderBook) SetQuoteRounding(rounding Rounding) {
+	if rounding != RoundDown && rounding != RoundUp && rounding != RoundHalfUp {
+		panic("unrecognized rounding policy")
+	}
+
+	ob.rounding = rounding
+}
+
+// SetStopPolicy sets policy of Stop Orders which trigger condition is already met on placement, it is StopActivate by default
+func (ob *OrderBook) SetStopPolicy(policy StopPolicy) {
+	if policy != StopActivate && policy != StopReject {
+		panic("unrecognized stop policy")
+	}
+
+	ob.stop = policy
+}
+
+// SetClock sets source of current time, it is the system clock by default
+func (ob *OrderBook) SetClock(clock Clock) {
+	ob.clock = clock
+}
+
+// Now returns current time of the OrderBook clock
+func (ob *OrderBook) Now() time.Time {
+	return ob.clock.Now()
+}
+
+// TickSize returns minimal Price step, it is the smallest decimal unit by default
+func (ob *OrderBook) TickSize() fpdecimal.Decimal {
+	if ob.tickSize.LessThanOrEqual(fpdecimal.Zero) {
+		return fpdecimal.FromIntScaled(1)
+	}
+
+	return ob.tickSize
+}
+
+// SetTickSize sets minimal Price step
+func (ob *OrderBook) SetTickSize(tickSize fpdecimal.Decimal) {
+	if tickSize.LessThanOrEqual(fpdecimal.Zero) {
+		panic(ErrInvalidPrice)
+	}
+
+	ob.tickSize = tickSize
+}
+
+// LastPrice returns Price of the last trade, it is zero if nothing was traded yet
+func (ob *OrderBook) LastPrice() fpdecimal.Decimal {
+	return ob.lastPrice
+}
+
 // GetOrder returns Order by id
 func (ob *OrderBook) GetOrder(orderID string) *Order {
 	order, ok := ob.orders[orderID]
@@ -36,8 +111,23 @@ func (ob *OrderBook) GetOrder(orderID string) *Order {
 	return order
 }
 
-// CancelOrder removes Order with given ID from the Order book or the Stop book
-func (ob *OrderBook) CancelOrder(orderID string) *Order {
+// CancelOrder removes Order with given ID from the Order book or the Stop book, OCO group members
+// (if group policy allows) are canceled too, exit Orders of bracket Order protect its filled quantity and stay. It returns canceled Order or nil if Order not found,
+// and Cancellation with IDs of all canceled and repriced Orders
+func (ob *OrderBook) CancelOrder(orderID string) (*Order, *Cancellation) {
+	cancellation := newCancellation()
+
+	order := ob.cancelOrder(orderID)
+	if order != nil {
+		cancellation.appendCanceled(order)
+		ob.cancelGroup(order, cancellation)
+	}
+	ob.repricePegged(cancellation)
+
+	return order, cancellation
+}
+
+func (ob *OrderBook) cancelOrder(orderID string) *Order {
 	order := ob.GetOrder(orderID)
 	if order == nil {
 		return nil
@@ -57,7 +147,23 @@ func (ob *OrderBook) CancelOrder(orderID string) *Order {
 
 // Process public method
 func (ob *OrderBook) Process(order *Order) (done *Done, err error) {
-	if order.IsMarketOrder() {
+	done, err = ob.process(order)
+	if err != nil {
+		return
+	}
+
+	ob.processTriggered(done)
+	ob.repricePegged(done)
+
+	return
+}
+
+func (ob *OrderBook) process(order *Order) (done *Done, err error) {
+	if order.TIF() == GTD && (order.ExpireAt().IsZero() || order.IsExpired(ob.Now())) {
+		return nil, ErrInvalidExpiry
+	}
+
+	if order.IsMarketOrder() || order.IsMarketToLimitOrder() {
 		return ob.processMarketOrder(order)
 	}
 
@@ -72,6 +178,105 @@ func (ob *OrderBook) Process(order *Order) (done *Done, err error) {
 	panic("unrecognized order type")
 }
 
+// ExpireOrders removes GTD Orders expired at given time from the Order book and the Stop book
+func (ob *OrderBook) ExpireOrders(now time.Time) *Expiry {
+	expiry := newExpiry(now)
+
+	for _, order := range ob.expire(now) {
+		expiry.appendExpired(order)
+	}
+
+	ob.repricePegged(expiry)
+
+	return expiry
+}
+
+// expire cancels GTD Orders expired at given time in order of their expiry
+func (ob *OrderBook) expire(now time.Time) (expired []*Order) {
+	for !ob.expiring.Empty() {
+		order := ob.expiring.Min()
+		if order.ExpireAt().After(now) {
+			break
+		}
+		ob.expiring.Remove(order)
+
+		// Order is already filled or canceled
+		if ob.orders[order.ID()] != order || !order.IsExpired(now) {
+			continue
+		}
+
+		expired = append(expired, ob.cancelOrder(order.ID()))
+	}
+
+	return
+}
+
+// expireMakers cancels resting GTD Orders expired by the OrderBook clock before matching, they are reported as canceled
+func (ob *OrderBook) expireMakers(done *Done) {
+	for _, order := range ob.expire(ob.Now()) {
+		done.appendCanceled(order)
+	}
+}
+
+// CloseSession closes trading session and cancels all DAY Orders, returns sorted IDs of canceled Orders
+// including pegged Orders and IDs of repriced pegged Orders
+func (ob *OrderBook) CloseSession() *Cancellation {
+	cancellation := newCancellation()
+	for _, order := range ob.orders {
+		if order.TIF() == DAY {
+			cancellation.appendCanceled(ob.cancelOrder(order.ID()))
+		}
+	}
+
+	ob.repricePegged(cancellation)
+
+	sort.Strings(cancellation.Canceled)
+
+	return cancellation
+}
+
+// canMarketOrderBeFilled checks FOK MARKET Orders within their protection, quantity can be in Quote mode
+func (ob *OrderBook) canMarketOrderBeFilled(side *OrderSide, order *Order, limit fpdecimal.Decimal, hasLimit bool) bool {
+	prices := side.Prices()
+	if len(prices) == 0 {
+		return false
+	}
+
+	priceLevel := prices[len(prices)-1]
+	if levels := order.ProtectionLevels(); levels > 0 && levels < len(prices) {
+		priceLevel = prices[levels-1]
+	}
+
+	if hasLimit && (order.Side() == Buy && limit.LessThan(priceLevel) || order.Side() == Sell && limit.GreaterThan(priceLevel)) {
+		priceLevel = limit
+	}
+
+	if order.IsQuote() {
+		return side.CanQuoteOrderBeFilled(order.Side(), priceLevel, order.Quantity())
+	}
+
+	return side.CanOrderBeFilled(order.Side(), priceLevel, order.Quantity())
+}
+
+// processTriggered processes Orders activated during matching, their Done is linked to the originating one.
+// Fills of activated Orders can activate further Stop Orders, they are queued and processed in the same loop
+func (ob *OrderBook) processTriggered(done *Done) {
+	for len(ob.triggered) > 0 {
+		order := ob.triggered[0]
+		ob.triggered = ob.triggered[1:]
+
+		// Order which can't be processed (expired, duplicated) is canceled and reported in the originating Done
+		triggeredDone, err := ob.process(order)
+		if err != nil {
+			order.Cancel()
+			done.appendCanceled(order)
+			continue
+		}
+
+		done.appendTriggered(triggeredDone)
+	}
+}
+
 // CalculateMarketPrice returns total market Price for requested quantity
 func (ob *OrderBook) CalculateMarketPrice(side Side, quantity fpdecimal.Decimal) (price fpdecimal.Decimal, err error) {
 	price = fpdecimal.Zero
@@ -114,12 +319,21 @@ func (ob *OrderBook) deleteStopOrder(order *Order) *Order {
 }
 
 func (ob *OrderBook) deleteStopOrderByID(orderID string) *Order {
-	return ob.Stop.RemoveByID(orderID)
+	order := ob.Stop.RemoveByID(orderID)
+	if order != nil {
+		delete(ob.orders, orderID)
+	}
+
+	return order
 }
 
 func (ob *OrderBook) deleteOrder(order *Order) *Order {
 	delete(ob.orders, order.ID())
 
+	if order.IsPegged() {
+		ob.removePegged(order)
+	}
+
 	if order.Side() == Buy {
 		ob.bids.Remove(order)
 	}
@@ -147,10 +361,11 @@ func (ob *OrderBook) processMarketOrder(marketOrder *Order) (done *Done, err err
 		return nil, ErrInvalidQuantity
 	}
 
-	var (
-		side *OrderSide
-		iter func() *OrderQueue
-	)
+	if marketOrder.IsMarketToLimitOrder() && ob.GetOrder(marketOrder.ID()) != nil {
+		return nil, ErrOrderExists
+	}
+
+	var side *OrderSide
 
 	if marketOrder.Side() == Buy {
 		side = ob.asks
@@ -158,20 +373,67 @@ func (ob *OrderBook) processMarketOrder(marketOrder *Order) (done *Done, err err
 		side = ob.bids
 	}
 
-	iter = side.BestPriceQueue
-
 	done = newDone(marketOrder)
+	ob.expireMakers(done)
+
+	if ob.checkGroup(marketOrder, done) {
+		return
+	}
+
+	level := side.BestPriceQueue()
+
+	var (
+		limit    fpdecimal.Decimal
+		hasLimit bool
+	)
+	if level != nil {
+		limit, hasLimit = marketOrder.protectionLimit(level.Price())
+	}
+
+	// FOK MARKET Order is executed only if it can be filled completely
+	if marketOrder.IsMarketOrder() && marketOrder.TIF() == FOK && !ob.canMarketOrderBeFilled(side, marketOrder, limit, hasLimit) {
+		marketOrder.Cancel()
+		done.appendCanceled(marketOrder)
+		done.Left = quantity
+		return
+	}
+
+	for levels := 0; quantity.GreaterThan(fpdecimal.Zero) && level != nil; levels++ {
+		// protection stops the sweep, remainder is canceled
+		if hasLimit && (marketOrder.Side() == Buy && level.Price().GreaterThan(limit) || marketOrder.Side() == Sell && level.Price().LessThan(limit)) ||
+			marketOrder.ProtectionLevels() > 0 && levels == marketOrder.ProtectionLevels() {
+			break
+		}
 
-	for quantity.GreaterThan(fpdecimal.Zero) && side.Len() > 0 {
-		bestPrice := iter()
 		if marketOrder.IsQuote() {
-			quantity = ob.processQueueQuote(bestPrice, quantity, done)
+			quantity = ob.processQueueQuote(level, quantity, done)
 		} else {
-			quantity = ob.processQueue(bestPrice, quantity, done)
+			quantity = ob.processQueue(level, quantity, done)
 		}
+		level = side.NextLevel(level.Price())
 	}
 
 	done.setLeftQuantity(&quantity)
+	// nothing is matched (protection, minimal quantity or all-or-none Orders), the whole Order is left
+	if len(done.Trades) == 0 {
+		done.Left = quantity
+	}
+
+	// MARKET Order is executed once, its OCO pair is canceled after any fill
+	if marketOrder.IsMarketOrder() && done.Processed.GreaterThan(fpdecimal.Zero) {
+		ob.appendToOCO(marketOrder, done)
+	}
+	ob.fillOrder(marketOrder, done.Processed, done)
+
+	// MARKET-TO-LIMIT remainder rests at the last fill Price
+	if marketOrder.IsMarketToLimitOrder() && done.Left.GreaterThan(fpdecimal.Zero) && done.Processed.GreaterThan(fpdecimal.Zero) {
+		marketOrder.orderType = TypeLimit
+		marketOrder.price = done.Trades[len(done.Trades)-1].Price
+		marketOrder.SetQuantity(done.Left)
+		ob.appendLimitOrder(marketOrder)
+		done.Stored = true
+		return done, nil
+	}
 
 	// If market GetOrder was not fulfilled then cancel it
 	if done.Left.GreaterThan(fpdecimal.Zero) {
@@ -194,41 +456,89 @@ func (ob *OrderBook) processLimitOrder(limitOrder *Order) (done *Done, err error
 	var (
 		side       *OrderSide
 		comparator func(fpdecimal.Decimal) bool
-		iter       func() *OrderQueue
 	)
 
 	if limitOrder.Side() == Buy {
 		side = ob.asks
-		comparator = limitOrder.price.GreaterThanOrEqual
+		comparator = func(price fpdecimal.Decimal) bool { return limitOrder.price.GreaterThanOrEqual(price) }
 	} else {
 		side = ob.bids
-		comparator = limitOrder.price.LessThanOrEqual
+		comparator = func(price fpdecimal.Decimal) bool { return limitOrder.price.LessThanOrEqual(price) }
 	}
 
-	iter = side.BestPriceQueue
-
 	done = newDone(limitOrder)
+	ob.expireMakers(done)
 
 	if ob.checkOCO(limitOrder, done) {
 		return
 	}
 
+	if ob.checkGroup(limitOrder, done) {
+		return
+	}
+
+	if limitOrder.IsPegged() {
+		price, ok := ob.pegPrice(limitOrder)
+		if !ok {
+			limitOrder.Cancel()
+			done.appendCanceled(limitOrder)
+			return
+		}
+		limitOrder.price = price
+	}
+
+	level := side.BestPriceQueue()
+
+	if limitOrder.IsPostOnly() && level != nil && comparator(level.Price()) {
+		if !ob.slidePostOnly(limitOrder, level.Price()) {
+			limitOrder.Cancel()
+			done.appendCanceled(limitOrder)
+			return
+		}
+		done.appendRepriced(limitOrder)
+	}
+
+	canBeFilled := side.CanOrderBeFilled
+	if limitOrder.IsQuote() {
+		canBeFilled = side.CanQuoteOrderBeFilled
+	}
+
 	if limitOrder.TIF() == FOK {
-		if !side.CanOrderBeFilled(limitOrder.Side(), limitOrder.price, quantity) {
+		if !canBeFilled(limitOrder.Side(), limitOrder.price, quantity) {
 			limitOrder.Cancel()
 			done.appendCanceled(limitOrder)
 			return
 		}
 	}
 
-	bestPrice := iter()
+	// all-or-none Order trades only if it can be filled completely, otherwise it rests untouched
+	if limitOrder.IsAllOrNone() {
+		if level == nil || !comparator(level.Price()) || !canBeFilled(limitOrder.Side(), limitOrder.price, quantity) {
+			level = nil
+		}
+	}
 
-	for quantity.GreaterThan(fpdecimal.Zero) && side.Len() > 0 && comparator(bestPrice.Price()) {
-		quantity = ob.processQueue(bestPrice, quantity, done)
-		bestPrice = iter()
+	// crossing Order with minimal execution quantity trades only if it can be satisfied immediately,
+	// otherwise it is canceled with any time in force, so it never rests at crossing Price
+	if limitOrder.MinQty().GreaterThan(fpdecimal.Zero) && level != nil && comparator(level.Price()) {
+		if !canBeFilled(limitOrder.Side(), limitOrder.price, limitOrder.MinQty()) {
+			limitOrder.Cancel()
+			done.appendCanceled(limitOrder)
+			return
+		}
+	}
+
+	for quantity.GreaterThan(fpdecimal.Zero) && level != nil && comparator(level.Price()) {
+		if limitOrder.IsQuote() {
+			quantity = ob.processQueueQuote(level, quantity, done)
+		} else {
+			quantity = ob.processQueue(level, quantity, done)
+		}
+		level = side.NextLevel(level.Price())
 	}
 
 	done.setLeftQuantity(&quantity)
+	ob.fillOrder(limitOrder, done.Processed, done)
 
 	if done.Left.GreaterThan(fpdecimal.Zero) || done.Processed.Equal(fpdecimal.Zero) {
 		if done.Left.GreaterThan(fpdecimal.Zero) {
@@ -236,31 +546,102 @@ func (ob *OrderBook) processLimitOrder(limitOrder *Order) (done *Done, err error
 		} else {
 			limitOrder.SetQuantity(done.Quantity)
 		}
-		ob.appendLimitOrder(limitOrder)
-		done.Stored = true
+
+		// resting Order is always in Base quantity
+		if limitOrder.IsQuote() {
+			limitOrder.SetQuantity(divRound(limitOrder.Quantity(), limitOrder.Price(), ob.rounding))
+			limitOrder.minQty = divRound(limitOrder.MinQty(), limitOrder.Price(), ob.rounding)
+			limitOrder.isQuote = false
+		}
+
+		if limitOrder.Quantity().GreaterThan(fpdecimal.Zero) {
+			ob.appendLimitOrder(limitOrder)
+			done.Stored = true
+		} else {
+			limitOrder.Cancel()
+			done.appendCanceled(limitOrder)
+		}
 	} else {
 		ob.appendToOCO(limitOrder, done)
 	}
 
-	// If IOC GetOrder was not fulfilled then cancel it
-	if limitOrder.TIF() == IOC && quantity.GreaterThan(fpdecimal.Zero) {
+	// If IOC or FOK GetOrder was not fulfilled then cancel it, FOK Order never rests
+	if (limitOrder.TIF() == IOC || limitOrder.TIF() == FOK) && quantity.GreaterThan(fpdecimal.Zero) && done.Stored {
 		limitOrder.SetTaker()
-		done.appendCanceled(ob.CancelOrder(limitOrder.ID()))
+		done.appendCanceled(ob.cancelOrder(limitOrder.ID()))
 		done.Stored = false
 	}
 
 	return
 }
 
+// slidePostOnly re-prices post-only Order one tick behind the opposite best Price
+func (ob *OrderBook) slidePostOnly(order *Order, bestPrice fpdecimal.Decimal) bool {
+	if order.PostOnly() != PostOnlySlide {
+		return false
+	}
+
+	var price fpdecimal.Decimal
+	if order.Side() == Buy {
+		price = bestPrice.Sub(ob.TickSize())
+	} else {
+		price = bestPrice.Add(ob.TickSize())
+	}
+
+	if price.LessThanOrEqual(fpdecimal.Zero) {
+		return false
+	}
+
+	order.price = price
+	return true
+}
+
 func (ob *OrderBook) processStopOrder(stopOrder *Order) (done *Done, err error) {
+	if _, ok := ob.orders[stopOrder.ID()]; ok {
+		return nil, ErrOrderExists
+	}
+
+	done = newDone(stopOrder)
+
+	if ob.checkOCO(stopOrder, done) {
+		return
+	}
+
+	if ob.checkGroup(stopOrder, done) {
+		return
+	}
+
+	if stopOrder.IsTrailingStop() && ob.lastPrice.GreaterThan(fpdecimal.Zero) {
+		if stop, ok := stopOrder.trailingStop(ob.lastPrice); ok {
+			stopOrder.setStopPrice(stop)
+		}
+	}
+
+	// trigger condition is already met by the last traded Price
+	if ob.lastPrice.GreaterThan(fpdecimal.Zero) && stopOrder.IsTriggered(ob.lastPrice) {
+		if ob.stop == StopReject {
+			stopOrder.Cancel()
+			done.appendCanceled(stopOrder)
+			return
+		}
+
+		stopOrder.ActivateStopOrder()
+		ob.triggered = append(ob.triggered, stopOrder)
+		done.appendActivated(stopOrder)
+		return
+	}
+
 	ob.Stop.Append(stopOrder)
 	ob.orders[stopOrder.ID()] = stopOrder
-	done = newDone(stopOrder)
+	ob.appendExpiring(stopOrder)
+	done.Stored = true
 	return
 }
 
 func (ob *OrderBook) appendLimitOrder(order *Order) {
 	if order.IsLimitOrder() {
+		order.splitIceberg()
+
 		if order.Side() == Buy {
 			ob.bids.Append(order)
 		}
@@ -269,6 +650,11 @@ func (ob *OrderBook) appendLimitOrder(order *Order) {
 		}
 
 		ob.orders[order.ID()] = order
+		ob.appendExpiring(order)
+
+		if order.IsPegged() {
+			ob.pegged = append(ob.pegged, order)
+		}
 
 		return
 	}
@@ -276,13 +662,23 @@ func (ob *OrderBook) appendLimitOrder(order *Order) {
 	panic("order has not LIMIT type")
 }
 
+func (ob *OrderBook) appendExpiring(order *Order) {
+	if order.TIF() == GTD {
+		// Order with the same ID and expiry can be left after fill
+		ob.expiring.Remove(order)
+		ob.expiring.Insert(order)
+	}
+}
+
 func (ob *OrderBook) activateStopOrders(price fpdecimal.Decimal) []*Order {
 	var activated []*Order
 	orders := ob.Stop.Activate(price)
 	for _, order := range orders {
 		order.ActivateStopOrder()
-		ob.appendLimitOrder(&order)
-		activated = append(activated, &order)
+		// activated Orders are matched when current matching is finished, in activation order
+		delete(ob.orders, order.ID())
+		ob.triggered = append(ob.triggered, order)
+		activated = append(activated, order)
 	}
 
 	return activated
@@ -298,28 +694,56 @@ func (ob *OrderBook) processQueue(orderQueue *OrderQueue, quantity fpdecimal.Dec
 	touch := false
 	price := orderQueue.Price()
 
-	for quantity.GreaterThan(fpdecimal.Zero) && orderQueue.Len() > 0 {
+	for i := 0; quantity.GreaterThan(fpdecimal.Zero) && i < orderQueue.Len(); {
+		o := orderQueue.Orders.At(i)
+		if !o.canMatch(quantity) {
+			i++
+			continue
+		}
+
 		touch = true
-		o := orderQueue.First()
+		length := orderQueue.Len()
 		orderQuantity := o.Quantity()
 		if quantity.LessThan(orderQuantity) {
 			done.appendOrder(o, quantity, price)
+			ob.fillOrder(o, quantity, done)
 			o.DecreaseQuantity(quantity)
-			orderQueue.UpdateVolume(o)
+			orderQueue.DecreaseVolume(o, quantity)
 			quantity = fpdecimal.Zero
+		} else if o.Reserve().GreaterThan(fpdecimal.Zero) {
+			// iceberg Order refreshes visible part from reserve and loses time priority
+			done.appendOrder(o, orderQuantity, price)
+			ob.fillOrder(o, orderQuantity, done)
+			orderQueue.Remove(o)
+			o.SetQuantity(fpdecimal.Zero)
+			o.splitIceberg()
+			orderQueue.Append(o)
+			quantity = quantity.Sub(orderQuantity)
 		} else {
 			ob.appendToOCO(o, done)
 			ob.deleteOrder(o)
 			done.appendOrder(o, orderQuantity, price)
+			ob.fillOrder(o, orderQuantity, done)
 			quantity = quantity.Sub(orderQuantity)
+			length--
+		}
+
+		// fill canceled other Orders of this level (OCO group, OCO pair), skipped Orders are checked again
+		if orderQueue.Len() != length {
+			i = 0
 		}
 	}
 
 	if touch {
+		ob.lastPrice = price
+
 		// activate Stop Orders for this Price level
 		for _, activatedOrder := range ob.activateStopOrders(price) {
 			done.appendActivated(activatedOrder)
 		}
+
+		// trailing Stop Orders follow the last traded Price
+		ob.Stop.Trail(price)
 	}
 
 	return quantity
@@ -345,7 +769,8 @@ func (ob *OrderBook) checkOCO(order *Order, done *Done) bool {
 
 	delete(ob.OCO, order.ID())
 
-	ob.cancelOCO(order.ID(), done)
+	order.Cancel()
+	done.appendCanceled(order)
 
 	return true
 }
@@ -388,5 +813,11 @@ func (ob *OrderBook) String() string {
 	builder.WriteString(ob.bids.String())
 	builder.WriteString("\n")
 
+	if len(ob.groups) > 0 {
+		builder.WriteString("Groups:")
+		builder.WriteString(ob.groupsString())
+		builder.WriteString("\n")
+	}
+
 	return builder.String()
 }
@@ -0,0 +1,48 @@
+package matchingo
+
+import (
+	"github.com/nikolaydubina/fpdecimal"
+)
+
+// fillOrder cancels OCO group members and submits Orders which are linked to filled Order
+// (bracket exit Orders, secondary Orders)
+func (ob *OrderBook) fillOrder(order *Order, quantity fpdecimal.Decimal, done *Done) {
+	if quantity.LessThanOrEqual(fpdecimal.Zero) {
+		return
+	}
+
+	ob.fillGroup(order, quantity, done)
+	ob.fillBracket(order, quantity, done)
+	ob.submitSecondary(order, done)
+}
+
+// submitSecondary submits secondary Orders after the first fill of primary Order,
+// they are processed when current matching is finished, all of them or none
+func (ob *OrderBook) submitSecondary(order *Order, done *Done) {
+	secondary := order.Secondary()
+	if len(secondary) == 0 {
+		return
+	}
+
+	order.secondary = nil
+
+	ids := make(map[string]struct{}, len(secondary))
+	valid := true
+	for _, o := range secondary {
+		if _, ok := ids[o.ID()]; ok || ob.GetOrder(o.ID()) != nil {
+			valid = false
+			break
+		}
+		ids[o.ID()] = struct{}{}
+	}
+
+	if !valid {
+		for _, o := range secondary {
+			o.Cancel()
+			done.appendCanceled(o)
+		}
+		return
+	}
+
+	ob.triggered = append(ob.triggered, secondary...)
+}
@@ -7,17 +7,22 @@ import (
 
 // OrderQueue stores and manage chain of Orders
 type OrderQueue struct {
-	volume fpdecimal.Decimal
-	price  fpdecimal.Decimal
-	Orders *deque.Deque[*Order]
+	volume       fpdecimal.Decimal
+	aonVolume    fpdecimal.Decimal
+	hiddenVolume fpdecimal.Decimal
+	hiddenLen    int
+	price        fpdecimal.Decimal
+	Orders       *deque.Deque[*Order]
 }
 
 // NewOrderQueue creates and initialize OrderQueue object
 func NewOrderQueue(price fpdecimal.Decimal) *OrderQueue {
 	return &OrderQueue{
-		price:  price,
-		volume: fpdecimal.Zero,
-		Orders: deque.New[*Order](),
+		price:        price,
+		volume:       fpdecimal.Zero,
+		aonVolume:    fpdecimal.Zero,
+		hiddenVolume: fpdecimal.Zero,
+		Orders:       deque.New[*Order](),
 	}
 }
 
@@ -31,19 +36,51 @@ func (oq *OrderQueue) Price() fpdecimal.Decimal {
 	return oq.price
 }
 
-// Volume returns total Orders volume
+// HiddenLen returns amount of hidden Orders in queue
+func (oq *OrderQueue) HiddenLen() int {
+	return oq.hiddenLen
+}
+
+// Volume returns total Orders volume, all-or-none and hidden Orders are not included
 func (oq *OrderQueue) Volume() fpdecimal.Decimal {
 	return oq.volume
 }
 
+// AONVolume returns total volume of all-or-none Orders
+func (oq *OrderQueue) AONVolume() fpdecimal.Decimal {
+	return oq.aonVolume
+}
+
+// HiddenVolume returns total volume of hidden Orders
+func (oq *OrderQueue) HiddenVolume() fpdecimal.Decimal {
+	return oq.hiddenVolume
+}
+
 // First returns top Order in queue
 func (oq *OrderQueue) First() *Order {
 	return oq.Orders.Front()
 }
 
-// Append adds Order to tail of the queue
+// Append adds Order to tail of the queue, displayed Orders are placed before hidden ones
 func (oq *OrderQueue) Append(o *Order) {
-	oq.volume = oq.volume.Add(o.Quantity())
+	if o.IsHidden() {
+		oq.hiddenVolume = oq.hiddenVolume.Add(o.Quantity())
+		oq.hiddenLen++
+		oq.Orders.PushBack(o)
+		return
+	}
+
+	if o.IsAllOrNone() {
+		oq.aonVolume = oq.aonVolume.Add(o.Quantity())
+	} else {
+		oq.volume = oq.volume.Add(o.Quantity())
+	}
+
+	if oq.hiddenLen > 0 {
+		oq.Orders.Insert(oq.Orders.Len()-oq.hiddenLen, o)
+		return
+	}
+
 	oq.Orders.PushBack(o)
 }
 
@@ -52,6 +89,21 @@ func (oq *OrderQueue) UpdateVolume(o *Order) {
 	oq.volume = oq.volume.Sub(o.Quantity())
 }
 
+// DecreaseVolume decreases volume on traded quantity of the Order
+func (oq *OrderQueue) DecreaseVolume(o *Order, quantity fpdecimal.Decimal) {
+	if o.IsHidden() {
+		oq.hiddenVolume = oq.hiddenVolume.Sub(quantity)
+		return
+	}
+
+	if o.IsAllOrNone() {
+		oq.aonVolume = oq.aonVolume.Sub(quantity)
+		return
+	}
+
+	oq.volume = oq.volume.Sub(quantity)
+}
+
 // Remove removes Order from the queue
 func (oq *OrderQueue) Remove(order *Order) bool {
 	index := oq.Orders.Index(func(o *Order) bool {
@@ -72,7 +124,10 @@ func (oq *OrderQueue) RemoveByID(id string) bool {
 func (oq *OrderQueue) RemoveIndex(index int) bool {
 	if index != -1 {
 		order := oq.Orders.At(index)
-		oq.volume = oq.volume.Sub(order.Quantity())
+		oq.DecreaseVolume(order, order.Quantity())
+		if order.IsHidden() {
+			oq.hiddenLen--
+		}
 		oq.Orders.Remove(index)
 		return true
 	}
@@ -85,7 +140,10 @@ func (oq *OrderQueue) Slice() []Order {
 	var slice []Order
 	for oq.Orders.Len() > 0 {
 		order := oq.Orders.PopFront()
-		oq.volume = oq.volume.Sub(order.Quantity())
+		oq.DecreaseVolume(order, order.Quantity())
+		if order.IsHidden() {
+			oq.hiddenLen--
+		}
 		slice = append(slice, *order)
 	}
 	return slice
@@ -81,6 +81,13 @@ func (os *OrderSide) Remove(order *Order) *Order {
 	return order
 }
 
+// Decrease decreases quantity of Order keeping its time priority
+func (os *OrderSide) Decrease(order *Order, quantity fpdecimal.Decimal) *Order {
+	os.prices[order.Price()].DecreaseVolume(order, quantity)
+	order.DecreaseQuantity(quantity)
+	return order
+}
+
 // Prices returns slice of prices
 func (os *OrderSide) Prices() []fpdecimal.Decimal {
 	return os.orderedPrices.Slice()
@@ -106,16 +113,14 @@ func (os *OrderSide) CanBuyOrderBeFilled(priceLevel, quantity fpdecimal.Decimal)
 		return false
 	}
 
-	volume := fpdecimal.Zero
 	for _, price := range os.Prices() {
-		if price.LessThanOrEqual(priceLevel) && volume.LessThan(quantity) {
-			volume = volume.Add(os.prices[price].Volume())
-		} else {
+		if price.GreaterThan(priceLevel) || quantity.LessThanOrEqual(fpdecimal.Zero) {
 			break
 		}
+		quantity = os.fillLevel(price, quantity)
 	}
 
-	return volume.GreaterThanOrEqual(quantity)
+	return quantity.LessThanOrEqual(fpdecimal.Zero)
 }
 
 // CanSellOrderBeFilled checks FOK Orders
@@ -125,16 +130,70 @@ func (os *OrderSide) CanSellOrderBeFilled(priceLevel, quantity fpdecimal.Decimal
 		return false
 	}
 
-	volume := fpdecimal.Zero
 	for _, price := range os.Prices() {
-		if price.GreaterThanOrEqual(priceLevel) && volume.LessThan(quantity) {
-			volume = volume.Add(os.prices[price].Volume())
-		} else {
+		if price.LessThan(priceLevel) || quantity.LessThanOrEqual(fpdecimal.Zero) {
+			break
+		}
+		quantity = os.fillLevel(price, quantity)
+	}
+
+	return quantity.LessThanOrEqual(fpdecimal.Zero)
+}
+
+// CanQuoteOrderBeFilled checks FOK Orders with quantity in Quote mode
+func (os *OrderSide) CanQuoteOrderBeFilled(side Side, priceLevel, quantity fpdecimal.Decimal) bool {
+
+	if os.Len() == 0 {
+		return false
+	}
+
+	for _, price := range os.Prices() {
+		if side == Buy && price.GreaterThan(priceLevel) || side == Sell && price.LessThan(priceLevel) {
 			break
 		}
+		if quantity.LessThanOrEqual(fpdecimal.Zero) {
+			break
+		}
+		quantity = os.fillLevel(price, quantity.Div(price)).Mul(price)
 	}
 
-	return volume.GreaterThanOrEqual(quantity)
+	return quantity.LessThanOrEqual(fpdecimal.Zero)
+}
+
+// fillLevel returns quantity left after matching at Price level the same way as OrderBook does,
+// Orders which can't be matched with the rest of quantity (all-or-none, minimal quantity) are skipped
+func (os *OrderSide) fillLevel(price, quantity fpdecimal.Decimal) fpdecimal.Decimal {
+	queue := os.prices[price]
+	for i := 0; i < queue.Len() && quantity.GreaterThan(fpdecimal.Zero); i++ {
+		o := queue.Orders.At(i)
+		if !o.canMatch(quantity) {
+			continue
+		}
+
+		// iceberg Order is refreshed from its reserve during the same matching
+		available := o.Quantity().Add(o.Reserve())
+		if quantity.LessThan(available) {
+			return fpdecimal.Zero
+		}
+		quantity = quantity.Sub(available)
+	}
+
+	return quantity
+}
+
+// referencePrice returns best Price of displayed Orders which are not pegged
+func (os *OrderSide) referencePrice() (fpdecimal.Decimal, bool) {
+	level := os.BestPriceQueue()
+	for level != nil {
+		if level.Orders.Index(func(o *Order) bool {
+			return !o.IsPegged() && !o.IsHidden()
+		}) != -1 {
+			return level.Price(), true
+		}
+		level = os.NextLevel(level.Price())
+	}
+
+	return fpdecimal.Zero, false
 }
 
 // BestPriceQueue returns best Orders queue
@@ -157,16 +216,20 @@ func (os *OrderSide) NextLevel(level fpdecimal.Decimal) *OrderQueue {
 	return nil
 }
 
-// String implements fmt.Stringer interface
+// String implements fmt.Stringer interface, hidden Orders are not displayed
 func (os *OrderSide) String() string {
 	sb := strings.Builder{}
 
 	for _, price := range os.orderedPrices.Slice() {
+		displayed := os.prices[price].Len() - os.prices[price].HiddenLen()
+		if displayed == 0 {
+			continue
+		}
 		sb.WriteString(
 			fmt.Sprintf(
 				"\n%s -> orders: %d, volume: %s",
 				price,
-				os.prices[price].Len(),
+				displayed,
 				os.prices[price].Volume(),
 			),
 		)
@@ -0,0 +1,116 @@
+package matchingo
+
+import (
+	"github.com/nikolaydubina/fpdecimal"
+)
+
+// pegPrice returns Price of pegged Order for current reference Prices, pegged Orders never cross the book
+func (ob *OrderBook) pegPrice(order *Order) (fpdecimal.Decimal, bool) {
+	bid, hasBid := ob.bids.referencePrice()
+	ask, hasAsk := ob.asks.referencePrice()
+
+	var (
+		price fpdecimal.Decimal
+		ok    bool
+	)
+
+	switch order.Peg() {
+	case PegPrimary:
+		if order.Side() == Buy {
+			price, ok = bid, hasBid
+		} else {
+			price, ok = ask, hasAsk
+		}
+	case PegMarket:
+		if order.Side() == Buy {
+			price, ok = ask, hasAsk
+		} else {
+			price, ok = bid, hasBid
+		}
+	case PegMidpoint:
+		price, ok = bid.Add(ask).Div(fpdecimal.FromInt(2)), hasBid && hasAsk
+	}
+
+	if !ok {
+		return fpdecimal.Zero, false
+	}
+
+	price = price.Add(order.PegOffset())
+
+	limit := order.PegLimit()
+	if order.Side() == Buy {
+		if limit.GreaterThan(fpdecimal.Zero) && price.GreaterThan(limit) {
+			price = limit
+		}
+		if best := ob.asks.BestPriceQueue(); best != nil && price.GreaterThanOrEqual(best.Price()) {
+			price = best.Price().Sub(ob.TickSize())
+		}
+	} else {
+		if limit.GreaterThan(fpdecimal.Zero) && price.LessThan(limit) {
+			price = limit
+		}
+		if best := ob.bids.BestPriceQueue(); best != nil && price.LessThanOrEqual(best.Price()) {
+			price = best.Price().Add(ob.TickSize())
+		}
+	}
+
+	if price.LessThanOrEqual(fpdecimal.Zero) {
+		return fpdecimal.Zero, false
+	}
+
+	return price, true
+}
+
+// pegReport receives pegged Orders which were canceled or repriced by the Order book
+type pegReport interface {
+	appendCanceled(order *Order)
+	appendRepriced(order *Order)
+}
+
+// repricePegged re-queues pegged Orders when the top of the book was changed,
+// pegged Orders which lost their reference Price are canceled, both are reported to the result of current operation
+func (ob *OrderBook) repricePegged(report pegReport) {
+	if len(ob.pegged) == 0 {
+		return
+	}
+
+	bid, _ := ob.bids.referencePrice()
+	ask, _ := ob.asks.referencePrice()
+	if bid.Equal(ob.pegBid) && ask.Equal(ob.pegAsk) {
+		return
+	}
+	ob.pegBid, ob.pegAsk = bid, ask
+
+	for _, order := range append([]*Order(nil), ob.pegged...) {
+		price, ok := ob.pegPrice(order)
+		if !ok {
+			ob.cancelOrder(order.ID())
+			report.appendCanceled(order)
+			continue
+		}
+
+		if price.Equal(order.Price()) {
+			continue
+		}
+
+		side := ob.asks
+		if order.Side() == Buy {
+			side = ob.bids
+		}
+
+		side.Remove(order)
+		order.price = price
+		side.Append(order)
+
+		report.appendRepriced(order)
+	}
+}
+
+func (ob *OrderBook) removePegged(order *Order) {
+	for i, o := range ob.pegged {
+		if o.ID() == order.ID() {
+			ob.pegged = append(ob.pegged[:i], ob.pegged[i+1:]...)
+			return
+		}
+	}
+}
@@ -4,20 +4,49 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/hashicorp/go-set"
 	"github.com/nikolaydubina/fpdecimal"
 )
 
 // StopBook implements facade to operations with Stop Orders
 type StopBook struct {
-	prices    map[string]*OrderQueue
+	above     *stopSide
+	below     *stopSide
 	orders    map[string]*Order
+	trailing  []*Order
 	numOrders int
 }
 
+// stopSide keeps Stop Orders of one trigger direction sorted in activation order
+type stopSide struct {
+	orderedPrices *set.TreeSet[fpdecimal.Decimal, set.Compare[fpdecimal.Decimal]]
+	prices        map[fpdecimal.Decimal]*OrderQueue
+	triggered     func(stop, price fpdecimal.Decimal) bool
+}
+
 // NewStopBook creates new OrderSide manager
 func NewStopBook() *StopBook {
 	return &StopBook{
-		prices: map[string]*OrderQueue{},
+		// rising Price reaches lower Stop Prices first
+		above: &stopSide{
+			orderedPrices: set.NewTreeSet[fpdecimal.Decimal, set.Compare[fpdecimal.Decimal]](func(a fpdecimal.Decimal, b fpdecimal.Decimal) int {
+				return a.Compare(b)
+			}),
+			prices: map[fpdecimal.Decimal]*OrderQueue{},
+			triggered: func(stop, price fpdecimal.Decimal) bool {
+				return stop.LessThanOrEqual(price)
+			},
+		},
+		// falling Price reaches higher Stop Prices first
+		below: &stopSide{
+			orderedPrices: set.NewTreeSet[fpdecimal.Decimal, set.Compare[fpdecimal.Decimal]](func(a fpdecimal.Decimal, b fpdecimal.Decimal) int {
+				return b.Compare(a)
+			}),
+			prices: map[fpdecimal.Decimal]*OrderQueue{},
+			triggered: func(stop, price fpdecimal.Decimal) bool {
+				return stop.GreaterThanOrEqual(price)
+			},
+		},
 		orders: map[string]*Order{},
 	}
 }
@@ -34,49 +63,73 @@ func (sb *StopBook) Append(o *Order) {
 		return
 	}
 
-	price := o.StopPrice()
-	strPrice := price.String()
-
-	priceQueue, ok := sb.prices[strPrice]
-	if !ok {
-		priceQueue = NewOrderQueue(price)
-		sb.prices[strPrice] = priceQueue
-	}
-	priceQueue.Append(o)
+	sb.enqueue(o)
 	sb.orders[o.ID()] = o
+	if o.IsTrailingStop() {
+		sb.trailing = append(sb.trailing, o)
+	}
 	sb.numOrders++
 }
 
-// Activate Orders by Stop Price
-func (sb *StopBook) Activate(price fpdecimal.Decimal) []Order {
-	strPrice := price.String()
+// Activate Orders which Stop Price was reached or crossed by Price, in Stop Price then time order
+func (sb *StopBook) Activate(price fpdecimal.Decimal) []*Order {
+	var slice []*Order
+	slice = sb.activate(slice, sb.above, price)
+	slice = sb.activate(slice, sb.below, price)
 
-	priceQueue, ok := sb.prices[strPrice]
-	if !ok {
-		return nil
-	}
+	sb.numOrders = sb.numOrders - len(slice)
+	return slice
+}
 
-	slice := priceQueue.Slice()
+func (sb *StopBook) activate(slice []*Order, side *stopSide, price fpdecimal.Decimal) []*Order {
+	for !side.orderedPrices.Empty() {
+		stop := side.orderedPrices.Min()
+		if !side.triggered(stop, price) {
+			break
+		}
+
+		priceQueue := side.prices[stop]
+		for priceQueue.Len() > 0 {
+			order := priceQueue.Orders.PopFront()
+			delete(sb.orders, order.ID())
+			sb.removeTrailing(order)
+			slice = append(slice, order)
+		}
 
-	if priceQueue.Len() == 0 {
-		delete(sb.prices, strPrice)
+		delete(side.prices, stop)
+		side.orderedPrices.Remove(stop)
 	}
 
-	sb.numOrders = sb.numOrders - len(slice)
 	return slice
 }
 
-// Remove removes Order from definite Price level
-func (sb *StopBook) Remove(order *Order) *Order {
-	price := order.StopPrice().String()
+// Trail moves Stop Price of trailing Orders after the last traded Price, returns moved Orders
+func (sb *StopBook) Trail(price fpdecimal.Decimal) []*Order {
+	var moved []*Order
+	for _, order := range sb.trailing {
+		stop, ok := order.trailingStop(price)
+		if !ok {
+			continue
+		}
 
-	priceQueue := sb.prices[price]
-	priceQueue.Remove(order)
+		sb.dequeue(order)
+		order.setStopPrice(stop)
+		sb.enqueue(order)
+		moved = append(moved, order)
+	}
+
+	return moved
+}
 
-	if priceQueue.Len() == 0 {
-		delete(sb.prices, price)
+// Remove removes Order from definite Price level
+func (sb *StopBook) Remove(order *Order) *Order {
+	if _, ok := sb.orders[order.ID()]; !ok {
+		return order
 	}
 
+	sb.dequeue(order)
+	delete(sb.orders, order.ID())
+	sb.removeTrailing(order)
 	sb.numOrders--
 	return order
 }
@@ -89,32 +142,79 @@ func (sb *StopBook) RemoveByID(id string) *Order {
 		return nil
 	}
 
-	price := order.StopPrice()
-	strPrice := price.String()
+	return sb.Remove(order)
+}
 
-	priceQueue, ok := sb.prices[strPrice]
+func (sb *StopBook) sideOf(o *Order) *stopSide {
+	if o.Trigger() == TriggerAbove {
+		return sb.above
+	}
+
+	return sb.below
+}
+
+func (sb *StopBook) enqueue(o *Order) {
+	side := sb.sideOf(o)
+	price := o.StopPrice()
+
+	priceQueue, ok := side.prices[price]
+	if !ok {
+		priceQueue = NewOrderQueue(price)
+		side.prices[price] = priceQueue
+		side.orderedPrices.Insert(price)
+	}
+	priceQueue.Append(o)
+}
+
+func (sb *StopBook) dequeue(o *Order) {
+	side := sb.sideOf(o)
+	price := o.StopPrice()
+
+	priceQueue, ok := side.prices[price]
 	if ok {
-		priceQueue.Remove(order)
+		priceQueue.Remove(o)
 		if priceQueue.Len() == 0 {
-			delete(sb.prices, strPrice)
+			delete(side.prices, price)
+			side.orderedPrices.Remove(price)
 		}
 	}
+}
+
+func (sb *StopBook) removeTrailing(o *Order) {
+	if !o.IsTrailingStop() {
+		return
+	}
 
-	delete(sb.orders, id)
-	sb.numOrders--
-	return order
+	for i, order := range sb.trailing {
+		if order.ID() == o.ID() {
+			sb.trailing = append(sb.trailing[:i], sb.trailing[i+1:]...)
+			return
+		}
+	}
 }
 
 // String implements fmt.Stringer interface
 func (sb *StopBook) String() string {
 	builder := strings.Builder{}
 
-	for price, queue := range sb.prices {
+	for _, price := range sb.above.orderedPrices.Slice() {
+		builder.WriteString(
+			fmt.Sprintf(
+				"\n%s %s -> size: %d",
+				TriggerAbove,
+				price,
+				sb.above.prices[price].Len(),
+			),
+		)
+	}
+
+	for _, price := range sb.below.orderedPrices.Slice() {
 		builder.WriteString(
 			fmt.Sprintf(
-				"\n%s -> size: %d",
+				"\n%s %s -> size: %d",
+				TriggerBelow,
 				price,
-				queue.Len(),
+				sb.below.prices[price].Len(),
 			),
 		)
 	}
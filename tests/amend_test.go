@@ -0,0 +1,182 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/gonevo/matchingo"
+	"github.com/nikolaydubina/fpdecimal"
+)
+
+func TestAmendOrder(t *testing.T) {
+	ob := matchingo.NewOrderBook()
+
+	ob.Process(matchingo.NewLimitOrder("order-1", matchingo.Buy, fpdecimal.FromInt(5), fpdecimal.FromInt(100), "", ""))
+	ob.Process(matchingo.NewLimitOrder("order-2", matchingo.Buy, fpdecimal.FromInt(5), fpdecimal.FromInt(100), "", ""))
+	ob.Process(matchingo.NewLimitOrder("order-3", matchingo.Sell, fpdecimal.FromInt(5), fpdecimal.FromInt(110), "", ""))
+
+	if _, err := ob.AmendOrder("order-4", fpdecimal.FromInt(1), fpdecimal.FromInt(100)); err != matchingo.ErrOrderNotFound {
+		t.Fatal("Wrong error", err)
+	}
+
+	if _, err := ob.AmendOrder("order-1", fpdecimal.Zero, fpdecimal.FromInt(100)); err != matchingo.ErrInvalidQuantity {
+		t.Fatal("Wrong error", err)
+	}
+
+	// reduced quantity keeps time priority
+	done, err := ob.AmendOrder("order-1", fpdecimal.FromInt(3), fpdecimal.FromInt(100))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !done.Stored || !done.Quantity.Equal(fpdecimal.FromInt(3)) || !ob.GetOrder("order-1").Quantity().Equal(fpdecimal.FromInt(3)) {
+		t.Fatal("Wrong amended quantity")
+	}
+
+	if ob.Depth().Bid["100.000"] != "8.000" {
+		t.Fatal("Wrong depth", ob.Depth().Bid)
+	}
+
+	done, _ = ob.Process(matchingo.NewLimitOrder("s1", matchingo.Sell, fpdecimal.FromInt(1), fpdecimal.FromInt(100), "", ""))
+	if done.GetTradeOrder("order-1") == nil {
+		t.Fatal("reduced order lost time priority")
+	}
+
+	// increased quantity re-queues order at the back
+	if _, err = ob.AmendOrder("order-1", fpdecimal.FromInt(6), fpdecimal.FromInt(100)); err != nil {
+		t.Fatal(err)
+	}
+
+	done, _ = ob.Process(matchingo.NewLimitOrder("s2", matchingo.Sell, fpdecimal.FromInt(1), fpdecimal.FromInt(100), "", ""))
+	if done.GetTradeOrder("order-2") == nil {
+		t.Fatal("increased order keeps time priority")
+	}
+
+	// Price change crossing the book matches
+	done, err = ob.AmendOrder("order-2", fpdecimal.FromInt(4), fpdecimal.FromInt(110))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if done.GetTradeOrder("order-3") == nil || !done.Processed.Equal(fpdecimal.FromInt(4)) || done.Stored {
+		t.Fatal("crossing amendment is not matched")
+	}
+
+	if done.Trades[0].Role != matchingo.TAKER || done.Trades[1].Role != matchingo.MAKER {
+		t.Fatal("Wrong role of amended order", done.Trades[0].Role)
+	}
+
+	if ob.GetOrder("order-2") != nil || !ob.GetOrder("order-3").Quantity().Equal(fpdecimal.FromInt(1)) {
+		t.Fatal("Wrong order book")
+	}
+}
+
+func TestAmendIcebergAndStopOrder(t *testing.T) {
+	ob := matchingo.NewOrderBook()
+
+	ob.Process(matchingo.NewIcebergOrder("iceberg", matchingo.Sell, fpdecimal.FromInt(10), fpdecimal.FromInt(2), fpdecimal.FromInt(100), "", ""))
+
+	// reserve is reduced first
+	if _, err := ob.AmendOrder("iceberg", fpdecimal.FromInt(5), fpdecimal.FromInt(100)); err != nil {
+		t.Fatal(err)
+	}
+
+	iceberg := ob.GetOrder("iceberg")
+	if !iceberg.Quantity().Equal(fpdecimal.FromInt(2)) || !iceberg.Reserve().Equal(fpdecimal.FromInt(3)) {
+		t.Fatal("Wrong iceberg quantity", iceberg.Quantity(), iceberg.Reserve())
+	}
+
+	if _, err := ob.AmendOrder("iceberg", fpdecimal.FromInt(1), fpdecimal.FromInt(100)); err != nil {
+		t.Fatal(err)
+	}
+
+	if !iceberg.Quantity().Equal(fpdecimal.FromInt(1)) || !iceberg.Reserve().Equal(fpdecimal.Zero) {
+		t.Fatal("Wrong iceberg quantity", iceberg.Quantity(), iceberg.Reserve())
+	}
+
+	ob.Process(matchingo.NewStopMarketOrder("stop", matchingo.Buy, fpdecimal.FromInt(5), fpdecimal.FromInt(120), ""))
+
+	if _, err := ob.AmendOrder("stop", fpdecimal.FromInt(3), fpdecimal.FromInt(100)); err != matchingo.ErrInvalidPrice {
+		t.Fatal("Wrong error", err)
+	}
+
+	done, err := ob.AmendOrder("stop", fpdecimal.FromInt(3), fpdecimal.Zero)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !done.Stored || !ob.GetOrder("stop").Quantity().Equal(fpdecimal.FromInt(3)) || ob.Stop.Len() != 1 {
+		t.Fatal("Wrong amended stop order")
+	}
+}
+
+func TestReplaceOrder(t *testing.T) {
+	ob := matchingo.NewOrderBook()
+
+	ob.Process(matchingo.NewLimitOrder("order-1", matchingo.Buy, fpdecimal.FromInt(5), fpdecimal.FromInt(100), "", "stop-1"))
+	ob.Process(matchingo.NewStopLimitOrder("stop-1", matchingo.Buy, fpdecimal.FromInt(5), fpdecimal.FromInt(120), fpdecimal.FromInt(120), "order-1"))
+	ob.Process(matchingo.NewLimitOrder("order-2", matchingo.Sell, fpdecimal.FromInt(5), fpdecimal.FromInt(110), "", ""))
+
+	if _, err := ob.Replace("order-3", matchingo.NewLimitOrder("order-4", matchingo.Buy, fpdecimal.FromInt(1), fpdecimal.FromInt(100), "", "")); err != matchingo.ErrOrderNotFound {
+		t.Fatal("Wrong error", err)
+	}
+
+	if _, err := ob.Replace("order-1", matchingo.NewLimitOrder("order-2", matchingo.Buy, fpdecimal.FromInt(1), fpdecimal.FromInt(100), "", "")); err != matchingo.ErrOrderExists {
+		t.Fatal("Wrong error", err)
+	}
+
+	if ob.GetOrder("order-1") == nil {
+		t.Fatal("rejected replace removes old order")
+	}
+
+	done, err := ob.Replace("order-1", matchingo.NewLimitOrder("order-1-new", matchingo.Buy, fpdecimal.FromInt(5), fpdecimal.FromInt(105), "", ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !done.Stored || len(done.Canceled) != 1 || done.Canceled[0] != "order-1" {
+		t.Fatal("Wrong replace result")
+	}
+
+	if ob.GetOrder("order-1") != nil || ob.GetOrder("order-1-new") == nil {
+		t.Fatal("Wrong order book")
+	}
+
+	if ob.GetOrder("order-1-new").OCO() != "stop-1" || ob.GetOrder("stop-1").OCO() != "order-1-new" {
+		t.Fatal("OCO links are not carried over")
+	}
+
+	// fill of the new Order cancels OCO pair of the old one
+	done, err = ob.Process(matchingo.NewLimitOrder("s1", matchingo.Sell, fpdecimal.FromInt(5), fpdecimal.FromInt(105), "", ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(done.Canceled) != 1 || done.Canceled[0] != "stop-1" || ob.Stop.Len() != 0 {
+		t.Fatal("Wrong OCO cancellation")
+	}
+
+	// filled Order can't be replaced
+	if _, err = ob.Replace("order-1-new", matchingo.NewLimitOrder("order-1-next", matchingo.Buy, fpdecimal.FromInt(5), fpdecimal.FromInt(105), "", "")); err != matchingo.ErrOrderNotFound {
+		t.Fatal("Wrong error", err)
+	}
+}
+
+func TestReplaceGroupOrder(t *testing.T) {
+	ob := matchingo.NewOrderBook()
+
+	ob.Process(matchingo.NewLimitOrder("g-1", matchingo.Buy, fpdecimal.FromInt(1), fpdecimal.FromInt(90), "", "").SetGroup("group"))
+	ob.Process(matchingo.NewLimitOrder("g-2", matchingo.Sell, fpdecimal.FromInt(1), fpdecimal.FromInt(110), "", "").SetGroup("group"))
+
+	if _, err := ob.Replace("g-1", matchingo.NewLimitOrder("g-3", matchingo.Buy, fpdecimal.FromInt(1), fpdecimal.FromInt(95), "", "")); err != nil {
+		t.Fatal(err)
+	}
+
+	if ob.GetOrder("g-3").Group() != "group" || ob.GetGroup("group").Orders()[0] != "g-3" {
+		t.Fatal("group is not carried over")
+	}
+
+	done, _ := ob.Process(matchingo.NewLimitOrder("s1", matchingo.Sell, fpdecimal.FromInt(1), fpdecimal.FromInt(95), "", ""))
+	if len(done.Canceled) != 1 || done.Canceled[0] != "g-2" {
+		t.Fatal("Wrong group cancellation")
+	}
+}
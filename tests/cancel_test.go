@@ -1,6 +1,7 @@
 package tests
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/gonevo/matchingo"
@@ -13,18 +14,18 @@ func TestCancelOrder(t *testing.T) {
 	ob.Process(matchingo.NewLimitOrder("order-1", matchingo.Sell, fpdecimal.FromInt(10), fpdecimal.FromInt(10), "", ""))
 	ob.Process(matchingo.NewStopLimitOrder("order-2", matchingo.Sell, fpdecimal.FromInt(10), fpdecimal.FromInt(10), fpdecimal.FromInt(11), ""))
 
-	if ob.CancelOrder("order-2").IsStopOrder() != true {
+	if order, _ := ob.CancelOrder("order-2"); order.IsStopOrder() != true {
 		t.Fatal("canceling stop order not work")
 	}
 
-	if ob.CancelOrder("order-2") != nil {
+	if order, _ := ob.CancelOrder("order-2"); order != nil {
 		t.Fatal("canceling stop order not work")
 	}
 
-	if ob.CancelOrder("order-1").IsLimitOrder() != true {
+	if order, _ := ob.CancelOrder("order-1"); order.IsLimitOrder() != true {
 		t.Fatal("canceling stop order not work")
 	}
-	if ob.CancelOrder("order-1") != nil {
+	if order, _ := ob.CancelOrder("order-1"); order != nil {
 		t.Fatal("canceling stop order not work")
 	}
 }
@@ -36,11 +37,80 @@ func TestCancelActivatedOrder(t *testing.T) {
 	ob.Process(matchingo.NewLimitOrder("order-2", matchingo.Sell, fpdecimal.FromInt(10), fpdecimal.FromInt(11), "", ""))
 	ob.Process(matchingo.NewLimitOrder("order-3", matchingo.Buy, fpdecimal.FromInt(10), fpdecimal.FromInt(11), "", ""))
 
-	if ob.CancelOrder("order-1").IsLimitOrder() != true {
+	if order, _ := ob.CancelOrder("order-1"); order.IsLimitOrder() != true {
 		t.Fatal("canceling stop order not work")
 	}
 
-	if ob.CancelOrder("order-1") != nil {
+	if order, _ := ob.CancelOrder("order-1"); order != nil {
 		t.Fatal("canceling stop order not work")
 	}
 }
+
+func TestCancelOrders(t *testing.T) {
+	ob := matchingo.NewOrderBook()
+
+	ob.Process(matchingo.NewLimitOrder("buy-90", matchingo.Buy, fpdecimal.FromInt(1), fpdecimal.FromInt(90), "", "").SetOwner("alice"))
+	ob.Process(matchingo.NewLimitOrder("buy-95", matchingo.Buy, fpdecimal.FromInt(1), fpdecimal.FromInt(95), "", "").SetOwner("bob"))
+	ob.Process(matchingo.NewLimitOrder("buy-99", matchingo.Buy, fpdecimal.FromInt(1), fpdecimal.FromInt(99), matchingo.DAY, ""))
+	ob.Process(matchingo.NewLimitOrder("sell-110", matchingo.Sell, fpdecimal.FromInt(1), fpdecimal.FromInt(110), "", "").SetOwner("alice"))
+	ob.Process(matchingo.NewStopLimitOrder("stop-120", matchingo.Buy, fpdecimal.FromInt(1), fpdecimal.FromInt(120), fpdecimal.FromInt(120), "").SetOwner("alice"))
+	ob.Process(matchingo.NewStopMarketOrder("stop-80", matchingo.Sell, fpdecimal.FromInt(1), fpdecimal.FromInt(80), ""))
+
+	canceled := ob.CancelOrders(matchingo.NewCancelFilter().SetOwner("alice").SetType(matchingo.TypeLimit)).Canceled
+	if strings.Join(canceled, ",") != "buy-90,sell-110" {
+		t.Fatal("Wrong canceled", canceled)
+	}
+
+	canceled = ob.CancelOrders(matchingo.NewCancelFilter().SetSide(matchingo.Buy).SetPriceRange(fpdecimal.FromInt(95), fpdecimal.FromInt(120)).SetTIF(matchingo.GTC)).Canceled
+	if strings.Join(canceled, ",") != "buy-95,stop-120" {
+		t.Fatal("Wrong canceled", canceled)
+	}
+
+	if ob.Stop.Len() != 1 || ob.GetOrder("buy-99") == nil {
+		t.Fatal("Wrong order book")
+	}
+
+	canceled = ob.CancelOrders(matchingo.NewCancelFilter()).Canceled
+	if strings.Join(canceled, ",") != "buy-99,stop-80" {
+		t.Fatal("Wrong canceled", canceled)
+	}
+
+	if ob.Stop.Len() != 0 || len(ob.Depth().Bid) != 0 || len(ob.Depth().Ask) != 0 {
+		t.Fatal("Wrong order book")
+	}
+}
+
+func TestCancelOrdersStopType(t *testing.T) {
+	ob := matchingo.NewOrderBook()
+
+	ob.Process(matchingo.NewLimitOrder("buy-90", matchingo.Buy, fpdecimal.FromInt(1), fpdecimal.FromInt(90), "", ""))
+	ob.Process(matchingo.NewStopLimitOrder("stop-120", matchingo.Buy, fpdecimal.FromInt(1), fpdecimal.FromInt(120), fpdecimal.FromInt(120), ""))
+	ob.Process(matchingo.NewStopMarketOrder("stop-80", matchingo.Sell, fpdecimal.FromInt(1), fpdecimal.FromInt(80), ""))
+
+	canceled := ob.CancelOrders(matchingo.NewCancelFilter().SetType(matchingo.TypeStop)).Canceled
+	if strings.Join(canceled, ",") != "stop-120,stop-80" {
+		t.Fatal("Wrong canceled", canceled)
+	}
+
+	if ob.Stop.Len() != 0 || ob.GetOrder("buy-90") == nil {
+		t.Fatal("Wrong order book")
+	}
+}
+
+func TestCancelOrdersGroup(t *testing.T) {
+	ob := matchingo.NewOrderBook()
+	ob.SetGroupPolicy("group", false, true)
+
+	ob.Process(matchingo.NewLimitOrder("buy-90", matchingo.Buy, fpdecimal.FromInt(1), fpdecimal.FromInt(90), "", "").SetGroup("group").SetOwner("alice"))
+	ob.Process(matchingo.NewLimitOrder("sell-110", matchingo.Sell, fpdecimal.FromInt(1), fpdecimal.FromInt(110), "", "").SetGroup("group"))
+	ob.Process(matchingo.NewLimitOrder("sell-120", matchingo.Sell, fpdecimal.FromInt(1), fpdecimal.FromInt(120), "", ""))
+
+	canceled := ob.CancelOrders(matchingo.NewCancelFilter().SetOwner("alice")).Canceled
+	if strings.Join(canceled, ",") != "buy-90,sell-110" {
+		t.Fatal("Wrong canceled", canceled)
+	}
+
+	if ob.GetOrder("sell-110") != nil || ob.GetOrder("sell-120") == nil {
+		t.Fatal("Wrong order book")
+	}
+}
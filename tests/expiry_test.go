@@ -0,0 +1,140 @@
+package tests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gonevo/matchingo"
+	"github.com/nikolaydubina/fpdecimal"
+)
+
+type testClock struct {
+	now time.Time
+}
+
+func (c *testClock) Now() time.Time {
+	return c.now
+}
+
+func TestExpireOrders(t *testing.T) {
+	clock := &testClock{now: time.Date(2023, 1, 1, 10, 0, 0, 0, time.UTC)}
+
+	ob := matchingo.NewOrderBook()
+	ob.SetClock(clock)
+
+	ob.Process(matchingo.NewLimitOrder("gtd-2", matchingo.Sell, fpdecimal.FromInt(1), fpdecimal.FromInt(100), "", "").SetExpiry(clock.now.Add(2 * time.Hour)))
+	ob.Process(matchingo.NewLimitOrder("gtd-1", matchingo.Buy, fpdecimal.FromInt(1), fpdecimal.FromInt(90), "", "").SetExpiry(clock.now.Add(time.Hour)))
+	ob.Process(matchingo.NewStopLimitOrder("gtd-stop", matchingo.Buy, fpdecimal.FromInt(1), fpdecimal.FromInt(110), fpdecimal.FromInt(110), "").SetExpiry(clock.now.Add(time.Hour)))
+	ob.Process(matchingo.NewLimitOrder("gtc", matchingo.Buy, fpdecimal.FromInt(1), fpdecimal.FromInt(80), matchingo.GTC, ""))
+
+	if _, err := ob.Process(matchingo.NewLimitOrder("expired", matchingo.Buy, fpdecimal.FromInt(1), fpdecimal.FromInt(80), "", "").SetExpiry(clock.now)); err != matchingo.ErrInvalidExpiry {
+		t.Fatal("expired order is accepted")
+	}
+
+	if _, err := ob.Process(matchingo.NewLimitOrder("no-expiry", matchingo.Buy, fpdecimal.FromInt(1), fpdecimal.FromInt(80), matchingo.GTD, "")); err != matchingo.ErrInvalidExpiry {
+		t.Fatal("GTD order without expiry is accepted")
+	}
+
+	expiry := ob.ExpireOrders(clock.now.Add(30 * time.Minute))
+	if len(expiry.Expired) != 0 {
+		t.Fatal("Wrong expired")
+	}
+
+	expiry = ob.ExpireOrders(clock.now.Add(2 * time.Hour))
+	if len(expiry.Expired) != 3 || expiry.Expired[0] != "gtd-1" || expiry.Expired[1] != "gtd-stop" || expiry.Expired[2] != "gtd-2" {
+		t.Fatal("Wrong expired", expiry)
+	}
+
+	if ob.GetOrder("gtd-1") != nil || ob.GetOrder("gtd-2") != nil || ob.Stop.Len() != 0 {
+		t.Fatal("expired orders are not removed")
+	}
+
+	if ob.GetOrder("gtc") == nil {
+		t.Fatal("GTC order is expired")
+	}
+}
+
+func TestExpiredStopTrigger(t *testing.T) {
+	clock := &testClock{now: time.Date(2023, 1, 1, 10, 0, 0, 0, time.UTC)}
+
+	ob := matchingo.NewOrderBook()
+	ob.SetClock(clock)
+
+	ob.Process(matchingo.NewStopLimitOrder("gtd-stop", matchingo.Buy, fpdecimal.FromInt(1), fpdecimal.FromInt(110), fpdecimal.FromInt(100), "").SetExpiry(clock.now.Add(time.Hour)))
+	ob.Process(matchingo.NewLimitOrder("order-s100", matchingo.Sell, fpdecimal.FromInt(2), fpdecimal.FromInt(100), "", ""))
+
+	clock.now = clock.now.Add(2 * time.Hour)
+
+	done, err := ob.Process(matchingo.NewLimitOrder("order-b100", matchingo.Buy, fpdecimal.FromInt(1), fpdecimal.FromInt(100), "", ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(done.Activated) != 0 || len(done.Triggered) != 0 {
+		t.Fatal("expired stop order is activated", done.Activated, done.Triggered)
+	}
+
+	if len(done.Canceled) != 1 || done.Canceled[0] != "gtd-stop" {
+		t.Fatal("expired stop order is not canceled", done.Canceled)
+	}
+
+	if ob.GetOrder("gtd-stop") != nil || ob.Stop.Len() != 0 {
+		t.Fatal("expired stop order is not removed")
+	}
+}
+
+func TestExpiredMaker(t *testing.T) {
+	clock := &testClock{now: time.Date(2023, 1, 1, 10, 0, 0, 0, time.UTC)}
+
+	ob := matchingo.NewOrderBook()
+	ob.SetClock(clock)
+
+	ob.Process(matchingo.NewLimitOrder("gtd", matchingo.Sell, fpdecimal.FromInt(1), fpdecimal.FromInt(100), "", "").SetExpiry(clock.now.Add(time.Minute)))
+	ob.Process(matchingo.NewLimitOrder("gtc", matchingo.Sell, fpdecimal.FromInt(1), fpdecimal.FromInt(110), "", ""))
+
+	clock.now = clock.now.Add(time.Hour)
+
+	done, err := ob.Process(matchingo.NewMarketOrder("market", matchingo.Buy, fpdecimal.FromInt(1)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if done.GetTradeOrder("gtd") != nil || done.GetTradeOrder("gtc") == nil {
+		t.Fatal("expired order is matched", done)
+	}
+
+	if len(done.Canceled) != 1 || done.Canceled[0] != "gtd" || ob.GetOrder("gtd") != nil {
+		t.Fatal("expired order is not canceled", done.Canceled)
+	}
+
+	if expiry := ob.ExpireOrders(clock.now); len(expiry.Expired) != 0 {
+		t.Fatal("Wrong expired", expiry)
+	}
+}
+
+func TestCloseSession(t *testing.T) {
+	ob := matchingo.NewOrderBook()
+
+	ob.Process(matchingo.NewLimitOrder("day-2", matchingo.Sell, fpdecimal.FromInt(1), fpdecimal.FromInt(100), matchingo.DAY, ""))
+	ob.Process(matchingo.NewLimitOrder("day-1", matchingo.Buy, fpdecimal.FromInt(1), fpdecimal.FromInt(90), matchingo.DAY, ""))
+	ob.Process(matchingo.NewStopLimitOrder("day-stop", matchingo.Buy, fpdecimal.FromInt(1), fpdecimal.FromInt(110), fpdecimal.FromInt(110), "").SetTIF(matchingo.DAY))
+	ob.Process(matchingo.NewLimitOrder("gtc", matchingo.Buy, fpdecimal.FromInt(1), fpdecimal.FromInt(80), matchingo.GTC, ""))
+	ob.Process(matchingo.NewStopLimitOrder("gtc-stop", matchingo.Buy, fpdecimal.FromInt(1), fpdecimal.FromInt(120), fpdecimal.FromInt(120), ""))
+
+	canceled := ob.CloseSession().Canceled
+	if len(canceled) != 3 || canceled[0] != "day-1" || canceled[1] != "day-2" || canceled[2] != "day-stop" {
+		t.Fatal("Wrong canceled", canceled)
+	}
+
+	if ob.GetOrder("day-1") != nil || ob.GetOrder("day-stop") != nil || ob.Stop.Len() != 1 {
+		t.Fatal("DAY orders are not removed")
+	}
+
+	if ob.GetOrder("gtc") == nil || ob.GetOrder("gtc-stop") == nil {
+		t.Fatal("GTC orders are canceled")
+	}
+
+	if len(ob.CloseSession().Canceled) != 0 {
+		t.Fatal("Wrong canceled")
+	}
+}
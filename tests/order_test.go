@@ -51,6 +51,254 @@ func TestOrder_Stop(t *testing.T) {
 	}()
 }
 
+func TestOrder_StopMarket(t *testing.T) {
+	func() {
+		defer func() {
+			if r := recover(); r == nil {
+				t.Errorf("NewStopMarketOrder should have panic!")
+			}
+		}()
+
+		matchingo.NewStopMarketOrder("id", matchingo.Buy, fpdecimal.FromInt(1), fpdecimal.FromInt(0), "")
+	}()
+
+	func() {
+		defer func() {
+			if r := recover(); r == nil {
+				t.Errorf("NewStopMarketOrder should have panic!")
+			}
+		}()
+
+		matchingo.NewStopMarketOrder("id", matchingo.Buy, fpdecimal.FromInt(0), fpdecimal.FromInt(1), "")
+	}()
+
+	order := matchingo.NewStopMarketQuoteOrder("id", matchingo.Buy, fpdecimal.FromInt(1), fpdecimal.FromInt(1), "")
+	order.ActivateStopOrder()
+
+	if !order.IsMarketOrder() || !order.IsQuote() {
+		t.Fatal("Wrong activated stop market order")
+	}
+}
+
+func TestOrder_Trailing(t *testing.T) {
+	func() {
+		defer func() {
+			if r := recover(); r == nil {
+				t.Errorf("NewTrailingStopMarketOrder should have panic!")
+			}
+		}()
+
+		matchingo.NewTrailingStopMarketOrder("id", matchingo.Buy, fpdecimal.FromInt(1), fpdecimal.FromInt(1), fpdecimal.FromInt(0), matchingo.TrailAbsolute, "")
+	}()
+
+	func() {
+		defer func() {
+			if r := recover(); r == nil {
+				t.Errorf("NewTrailingStopLimitOrder should have panic!")
+			}
+		}()
+
+		matchingo.NewTrailingStopLimitOrder("id", matchingo.Buy, fpdecimal.FromInt(1), fpdecimal.FromInt(1), fpdecimal.FromInt(1), fpdecimal.FromInt(100), matchingo.TrailPercent, "")
+	}()
+}
+
+func TestOrder_Iceberg(t *testing.T) {
+	func() {
+		defer func() {
+			if r := recover(); r == nil {
+				t.Errorf("NewIcebergOrder should have panic!")
+			}
+		}()
+
+		matchingo.NewIcebergOrder("id", matchingo.Buy, fpdecimal.FromInt(1), fpdecimal.FromInt(2), fpdecimal.FromInt(1), "", "")
+	}()
+}
+
+func TestOrder_Trigger(t *testing.T) {
+	func() {
+		defer func() {
+			if r := recover(); r == nil {
+				t.Errorf("SetTrigger should have panic!")
+			}
+		}()
+
+		matchingo.NewLimitOrder("id", matchingo.Buy, fpdecimal.FromInt(1), fpdecimal.FromInt(1), "", "").SetTrigger(matchingo.TriggerAbove)
+	}()
+
+	order := matchingo.NewStopLimitOrder("id", matchingo.Sell, fpdecimal.FromInt(1), fpdecimal.FromInt(10), fpdecimal.FromInt(10), "")
+
+	if !order.IsTriggered(fpdecimal.FromInt(9)) || order.IsTriggered(fpdecimal.FromInt(11)) {
+		t.Fatal("Wrong trigger condition")
+	}
+
+	order.SetTrigger(matchingo.TriggerAbove)
+
+	if order.IsTriggered(fpdecimal.FromInt(9)) || !order.IsTriggered(fpdecimal.FromInt(11)) {
+		t.Fatal("Wrong trigger condition")
+	}
+}
+
+func TestOrder_Bracket(t *testing.T) {
+	func() {
+		defer func() {
+			if r := recover(); r == nil {
+				t.Errorf("NewBracketOrder should have panic!")
+			}
+		}()
+
+		matchingo.NewBracketOrder("id", matchingo.Buy, fpdecimal.FromInt(1), fpdecimal.FromInt(10), fpdecimal.FromInt(9), fpdecimal.FromInt(8), "")
+	}()
+
+	func() {
+		defer func() {
+			if r := recover(); r == nil {
+				t.Errorf("NewBracketOrder should have panic!")
+			}
+		}()
+
+		matchingo.NewBracketOrder("id", matchingo.Sell, fpdecimal.FromInt(1), fpdecimal.FromInt(10), fpdecimal.FromInt(9), fpdecimal.FromInt(8), "")
+	}()
+}
+
+func TestOrder_Secondary(t *testing.T) {
+	func() {
+		defer func() {
+			if r := recover(); r == nil {
+				t.Errorf("SetSecondary should have panic!")
+			}
+		}()
+
+		matchingo.NewLimitOrder("id", matchingo.Buy, fpdecimal.FromInt(1), fpdecimal.FromInt(1), "", "").SetSecondary()
+	}()
+
+	func() {
+		defer func() {
+			if r := recover(); r == nil {
+				t.Errorf("SetSecondary should have panic!")
+			}
+		}()
+
+		matchingo.NewLimitOrder("id", matchingo.Buy, fpdecimal.FromInt(1), fpdecimal.FromInt(1), "", "").SetSecondary(
+			matchingo.NewLimitOrder("id", matchingo.Sell, fpdecimal.FromInt(1), fpdecimal.FromInt(2), "", ""),
+		)
+	}()
+}
+
+func TestOrder_Group(t *testing.T) {
+	func() {
+		defer func() {
+			if r := recover(); r == nil {
+				t.Errorf("SetGroup should have panic!")
+			}
+		}()
+
+		matchingo.NewLimitOrder("id", matchingo.Buy, fpdecimal.FromInt(1), fpdecimal.FromInt(1), "", "").SetGroup("")
+	}()
+}
+
+func TestOrder_Protection(t *testing.T) {
+	func() {
+		defer func() {
+			if r := recover(); r == nil {
+				t.Errorf("SetProtectionPrice should have panic!")
+			}
+		}()
+
+		matchingo.NewLimitOrder("id", matchingo.Buy, fpdecimal.FromInt(1), fpdecimal.FromInt(1), "", "").SetProtectionPrice(fpdecimal.FromInt(1))
+	}()
+
+	func() {
+		defer func() {
+			if r := recover(); r == nil {
+				t.Errorf("SetProtectionPercent should have panic!")
+			}
+		}()
+
+		matchingo.NewMarketOrder("id", matchingo.Buy, fpdecimal.FromInt(1)).SetProtectionPercent(fpdecimal.FromInt(100))
+	}()
+
+	func() {
+		defer func() {
+			if r := recover(); r == nil {
+				t.Errorf("SetProtectionLevels should have panic!")
+			}
+		}()
+
+		matchingo.NewMarketToLimitOrder("id", matchingo.Buy, fpdecimal.FromInt(1)).SetProtectionLevels(1)
+	}()
+}
+
+func TestOrder_PostOnly(t *testing.T) {
+	func() {
+		defer func() {
+			if r := recover(); r == nil {
+				t.Errorf("SetPostOnly should have panic!")
+			}
+		}()
+
+		matchingo.NewMarketOrder("id", matchingo.Buy, fpdecimal.FromInt(1)).SetPostOnly(matchingo.PostOnlyReject)
+	}()
+}
+
+func TestOrder_SetTIF(t *testing.T) {
+	func() {
+		defer func() {
+			if r := recover(); r == nil {
+				t.Errorf("SetTIF should have panic!")
+			}
+		}()
+
+		matchingo.NewStopLimitOrder("id", matchingo.Buy, fpdecimal.FromInt(1), fpdecimal.FromInt(1), fpdecimal.FromInt(1), "").SetTIF("FAKE")
+	}()
+
+	func() {
+		defer func() {
+			if r := recover(); r == nil {
+				t.Errorf("SetTIF should have panic!")
+			}
+		}()
+
+		matchingo.NewStopMarketOrder("id", matchingo.Buy, fpdecimal.FromInt(1), fpdecimal.FromInt(1), "").SetTIF(matchingo.IOC)
+	}()
+
+	func() {
+		defer func() {
+			if r := recover(); r == nil {
+				t.Errorf("SetTIF should have panic!")
+			}
+		}()
+
+		matchingo.NewMarketOrder("id", matchingo.Buy, fpdecimal.FromInt(1)).SetTIF(matchingo.GTC)
+	}()
+
+	if matchingo.NewMarketQuoteOrder("id", matchingo.Buy, fpdecimal.FromInt(1)).SetTIF(matchingo.FOK).TIF() != matchingo.FOK {
+		t.Fatal("Wrong TIF")
+	}
+
+	order := matchingo.NewStopLimitOrder("id", matchingo.Buy, fpdecimal.FromInt(1), fpdecimal.FromInt(1), fpdecimal.FromInt(1), "").SetTIF(matchingo.DAY)
+	if order.TIF() != matchingo.DAY {
+		t.Fatal("Wrong TIF")
+	}
+
+	order = matchingo.NewStopLimitOrder("id", matchingo.Buy, fpdecimal.FromInt(1), fpdecimal.FromInt(1), fpdecimal.FromInt(1), "").SetTIF(matchingo.IOC)
+	order.ActivateStopOrder()
+	if !order.IsLimitOrder() || order.TIF() != matchingo.IOC {
+		t.Fatal("Wrong TIF after activation")
+	}
+}
+
+func TestOrder_MinQty(t *testing.T) {
+	func() {
+		defer func() {
+			if r := recover(); r == nil {
+				t.Errorf("SetMinQty should have panic!")
+			}
+		}()
+
+		matchingo.NewLimitOrder("id", matchingo.Buy, fpdecimal.FromInt(1), fpdecimal.FromInt(1), "", "").SetMinQty(fpdecimal.FromInt(2))
+	}()
+}
+
 func TestOrder_Limit(t *testing.T) {
 	func() {
 		defer func() {
@@ -2,6 +2,7 @@ package tests
 
 import (
 	"fmt"
+	"strings"
 	"testing"
 	"time"
 
@@ -291,6 +292,320 @@ func TestOCOProcessLimit(t *testing.T) {
 	}
 }
 
+func TestBracketProcess(t *testing.T) {
+	ob := matchingo.NewOrderBook()
+
+	ob.Process(matchingo.NewLimitOrder("sell-100", matchingo.Sell, fpdecimal.FromInt(2), fpdecimal.FromInt(100), "", ""))
+
+	done, err := ob.Process(matchingo.NewBracketOrder("entry", matchingo.Buy, fpdecimal.FromInt(5), fpdecimal.FromInt(100), fpdecimal.FromInt(110), fpdecimal.FromInt(90), ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !done.Stored || len(done.Triggered) != 2 {
+		t.Fatal("Wrong triggered")
+	}
+
+	takeProfit := done.Triggered[0].Order
+	stopLoss := done.Triggered[1].Order
+
+	if takeProfit.ID() != "entry-tp-1" || !takeProfit.IsLimitOrder() || takeProfit.Side() != matchingo.Sell ||
+		!takeProfit.Quantity().Equal(fpdecimal.FromInt(2)) || !takeProfit.Price().Equal(fpdecimal.FromInt(110)) {
+		t.Fatal("Wrong take-profit order")
+	}
+
+	if stopLoss.ID() != "entry-sl-1" || !stopLoss.IsStopOrder() || stopLoss.Side() != matchingo.Sell ||
+		!stopLoss.Quantity().Equal(fpdecimal.FromInt(2)) || !stopLoss.StopPrice().Equal(fpdecimal.FromInt(90)) {
+		t.Fatal("Wrong stop-loss order")
+	}
+
+	if takeProfit.OCO() != "entry-sl-1" || stopLoss.OCO() != "entry-tp-1" {
+		t.Fatal("Wrong OCO pair")
+	}
+
+	// resting entry Order is filled partially
+	done, err = ob.Process(matchingo.NewLimitOrder("s1", matchingo.Sell, fpdecimal.FromInt(1), fpdecimal.FromInt(100), "", ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(done.Triggered) != 2 || done.Triggered[0].Order.ID() != "entry-tp-2" || !done.Triggered[0].Order.Quantity().Equal(fpdecimal.FromInt(1)) {
+		t.Fatal("Wrong triggered")
+	}
+
+	// take-profit fill cancels its stop-loss
+	done, err = ob.Process(matchingo.NewLimitOrder("b1", matchingo.Buy, fpdecimal.FromInt(2), fpdecimal.FromInt(110), "", ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if done.GetTradeOrder("entry-tp-1") == nil || len(done.Canceled) != 1 || done.Canceled[0] != "entry-sl-1" {
+		t.Fatal("Wrong OCO cancellation")
+	}
+
+	if ob.Stop.Len() != 1 {
+		t.Fatal("Wrong stop book")
+	}
+
+	// canceled partially filled entry Order keeps exit Orders of its filled quantity
+	order, cancellation := ob.CancelOrder("entry")
+	if order == nil || len(cancellation.Canceled) != 1 || cancellation.Canceled[0] != "entry" {
+		t.Fatal("Wrong cancellation", cancellation)
+	}
+
+	if ob.GetOrder("entry-tp-2") == nil || ob.GetOrder("entry-sl-2") == nil || ob.Stop.Len() != 1 {
+		t.Fatal("exit orders of filled quantity are canceled")
+	}
+
+	// unfilled remainder of canceled entry Order doesn't create exit Orders
+	done, err = ob.Process(matchingo.NewLimitOrder("s2", matchingo.Sell, fpdecimal.FromInt(2), fpdecimal.FromInt(100), "", ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(done.Trades) != 0 || len(done.Triggered) != 0 || ob.GetOrder("entry-tp-3") != nil {
+		t.Fatal("canceled entry order submits exit orders")
+	}
+}
+
+func TestBracketExitCollisionProcess(t *testing.T) {
+	ob := matchingo.NewOrderBook()
+
+	ob.Process(matchingo.NewLimitOrder("entry-tp-1", matchingo.Sell, fpdecimal.FromInt(1), fpdecimal.FromInt(120), "", ""))
+	ob.Process(matchingo.NewLimitOrder("sell-100", matchingo.Sell, fpdecimal.FromInt(2), fpdecimal.FromInt(100), "", ""))
+
+	done, err := ob.Process(matchingo.NewBracketOrder("entry", matchingo.Buy, fpdecimal.FromInt(2), fpdecimal.FromInt(100), fpdecimal.FromInt(110), fpdecimal.FromInt(90), ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(done.Triggered) != 0 || len(done.Canceled) != 2 || done.Canceled[0] != "entry-tp-1" || done.Canceled[1] != "entry-sl-1" {
+		t.Fatal("Wrong canceled", done.Canceled)
+	}
+
+	if !ob.GetOrder("entry-tp-1").Price().Equal(fpdecimal.FromInt(120)) || ob.Stop.Len() != 0 {
+		t.Fatal("Wrong orderbook state")
+	}
+}
+
+func TestBracketStopLossProcess(t *testing.T) {
+	ob := matchingo.NewOrderBook()
+
+	ob.Process(matchingo.NewLimitOrder("sell-100", matchingo.Sell, fpdecimal.FromInt(1), fpdecimal.FromInt(100), "", ""))
+	ob.Process(matchingo.NewLimitOrder("buy-90", matchingo.Buy, fpdecimal.FromInt(5), fpdecimal.FromInt(90), "", ""))
+	ob.Process(matchingo.NewBracketOrder("entry", matchingo.Buy, fpdecimal.FromInt(1), fpdecimal.FromInt(100), fpdecimal.FromInt(110), fpdecimal.FromInt(95), ""))
+
+	if ob.GetOrder("entry") != nil || ob.GetOrder("entry-tp-1") == nil || ob.GetOrder("entry-sl-1") == nil {
+		t.Fatal("Wrong children")
+	}
+
+	done, err := ob.Process(matchingo.NewLimitOrder("s1", matchingo.Sell, fpdecimal.FromInt(1), fpdecimal.FromInt(90), "", ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(done.Activated) != 1 || done.Activated[0] != "entry-sl-1" || len(done.Triggered) != 1 {
+		t.Fatal("Wrong activated")
+	}
+
+	stopLoss := done.Triggered[0]
+	if stopLoss.GetTradeOrder("buy-90") == nil || len(stopLoss.Canceled) != 1 || stopLoss.Canceled[0] != "entry-tp-1" {
+		t.Fatal("stop-loss doesn't cancel take-profit")
+	}
+
+	if ob.GetOrder("entry-tp-1") != nil {
+		t.Fatal("take-profit is not canceled")
+	}
+}
+
+func TestOTOProcess(t *testing.T) {
+	ob := matchingo.NewOrderBook()
+
+	ob.Process(matchingo.NewLimitOrder("sell-100", matchingo.Sell, fpdecimal.FromInt(1), fpdecimal.FromInt(100), "", ""))
+
+	primary := matchingo.NewLimitOrder("primary", matchingo.Buy, fpdecimal.FromInt(2), fpdecimal.FromInt(100), "", "").SetSecondary(
+		matchingo.NewLimitOrder("secondary", matchingo.Sell, fpdecimal.FromInt(2), fpdecimal.FromInt(120), "", ""),
+	)
+
+	done, err := ob.Process(primary)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(done.Triggered) != 1 || done.Triggered[0].Order.ID() != "secondary" || !done.Triggered[0].Stored {
+		t.Fatal("Wrong triggered")
+	}
+
+	if len(primary.Secondary()) != 0 {
+		t.Fatal("secondary orders are submitted twice")
+	}
+
+	// next fill of primary Order doesn't submit anything
+	done, err = ob.Process(matchingo.NewLimitOrder("s1", matchingo.Sell, fpdecimal.FromInt(1), fpdecimal.FromInt(100), "", ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(done.Triggered) != 0 {
+		t.Fatal("Wrong triggered")
+	}
+}
+
+func TestOTOCOProcess(t *testing.T) {
+	ob := matchingo.NewOrderBook()
+
+	ob.Process(matchingo.NewLimitOrder("primary", matchingo.Buy, fpdecimal.FromInt(2), fpdecimal.FromInt(100), "", "").SetSecondary(
+		matchingo.NewLimitOrder("take-profit", matchingo.Sell, fpdecimal.FromInt(2), fpdecimal.FromInt(120), "", "stop-loss"),
+		matchingo.NewStopMarketOrder("stop-loss", matchingo.Sell, fpdecimal.FromInt(2), fpdecimal.FromInt(90), "take-profit"),
+	))
+
+	done, err := ob.Process(matchingo.NewLimitOrder("s1", matchingo.Sell, fpdecimal.FromInt(2), fpdecimal.FromInt(100), "", ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(done.Triggered) != 2 || done.Triggered[0].Order.ID() != "take-profit" || done.Triggered[1].Order.ID() != "stop-loss" {
+		t.Fatal("Wrong triggered")
+	}
+
+	if ob.Stop.Len() != 1 || ob.GetOrder("take-profit") == nil {
+		t.Fatal("secondary orders are not stored")
+	}
+
+	done, err = ob.Process(matchingo.NewLimitOrder("b1", matchingo.Buy, fpdecimal.FromInt(2), fpdecimal.FromInt(120), "", ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(done.Canceled) != 1 || done.Canceled[0] != "stop-loss" || ob.Stop.Len() != 0 {
+		t.Fatal("Wrong OCO cancellation")
+	}
+}
+
+func TestOTOAtomicProcess(t *testing.T) {
+	ob := matchingo.NewOrderBook()
+
+	ob.Process(matchingo.NewLimitOrder("sell-100", matchingo.Sell, fpdecimal.FromInt(1), fpdecimal.FromInt(100), "", ""))
+	ob.Process(matchingo.NewLimitOrder("existing", matchingo.Sell, fpdecimal.FromInt(1), fpdecimal.FromInt(130), "", ""))
+
+	done, err := ob.Process(matchingo.NewLimitOrder("primary", matchingo.Buy, fpdecimal.FromInt(1), fpdecimal.FromInt(100), "", "").SetSecondary(
+		matchingo.NewLimitOrder("secondary", matchingo.Sell, fpdecimal.FromInt(1), fpdecimal.FromInt(120), "", ""),
+		matchingo.NewLimitOrder("existing", matchingo.Sell, fpdecimal.FromInt(1), fpdecimal.FromInt(125), "", ""),
+	))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(done.Triggered) != 0 || len(done.Canceled) != 2 {
+		t.Fatal("secondary orders are not rejected together")
+	}
+
+	if ob.GetOrder("secondary") != nil {
+		t.Fatal("secondary order is submitted")
+	}
+}
+
+func TestOCOGroupProcess(t *testing.T) {
+	ob := matchingo.NewOrderBook()
+
+	ob.Process(matchingo.NewLimitOrder("g-1", matchingo.Buy, fpdecimal.FromInt(2), fpdecimal.FromInt(90), "", "").SetGroup("group"))
+	ob.Process(matchingo.NewLimitOrder("g-2", matchingo.Buy, fpdecimal.FromInt(2), fpdecimal.FromInt(95), "", "").SetGroup("group"))
+	ob.Process(matchingo.NewStopLimitOrder("g-3", matchingo.Buy, fpdecimal.FromInt(2), fpdecimal.FromInt(110), fpdecimal.FromInt(110), "").SetGroup("group"))
+
+	group := ob.GetGroup("group")
+	if group == nil || len(group.Orders()) != 3 || group.IsTriggered() {
+		t.Fatal("Wrong group")
+	}
+
+	// partial fill doesn't trigger group by default
+	done, err := ob.Process(matchingo.NewLimitOrder("s1", matchingo.Sell, fpdecimal.FromInt(1), fpdecimal.FromInt(95), "", ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(done.Canceled) != 0 || group.IsTriggered() {
+		t.Fatal("group is triggered by partial fill")
+	}
+
+	done, err = ob.Process(matchingo.NewLimitOrder("s2", matchingo.Sell, fpdecimal.FromInt(1), fpdecimal.FromInt(95), "", ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(done.Canceled) != 2 || done.Canceled[0] != "g-1" || done.Canceled[1] != "g-3" {
+		t.Fatal("Wrong canceled", done.Canceled)
+	}
+
+	if !group.IsTriggered() || group.Filled() != "g-2" || ob.Stop.Len() != 0 || ob.GetOrder("g-1") != nil {
+		t.Fatal("Wrong group state")
+	}
+
+	if !strings.Contains(ob.String(), "group -> orders: [g-1, g-2, g-3]") {
+		t.Fatal("group is not in snapshot")
+	}
+
+	// late member of triggered group is canceled
+	done, err = ob.Process(matchingo.NewLimitOrder("g-4", matchingo.Buy, fpdecimal.FromInt(2), fpdecimal.FromInt(80), "", "").SetGroup("group"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !done.Order.IsCanceled() || done.Stored {
+		t.Fatal("late member is not canceled")
+	}
+}
+
+func TestOCOGroupSameLevelProcess(t *testing.T) {
+	ob := matchingo.NewOrderBook()
+
+	ob.Process(matchingo.NewLimitOrder("b", matchingo.Sell, fpdecimal.FromInt(10), fpdecimal.FromInt(100), "", "").SetAllOrNone().SetGroup("group"))
+	ob.Process(matchingo.NewLimitOrder("c", matchingo.Sell, fpdecimal.FromInt(10), fpdecimal.FromInt(100), "", "").SetAllOrNone())
+	ob.Process(matchingo.NewLimitOrder("a", matchingo.Sell, fpdecimal.FromInt(1), fpdecimal.FromInt(100), "", "").SetGroup("group"))
+	ob.Process(matchingo.NewLimitOrder("d", matchingo.Sell, fpdecimal.FromInt(1), fpdecimal.FromInt(100), "", ""))
+
+	done, err := ob.Process(matchingo.NewLimitOrder("order-b100", matchingo.Buy, fpdecimal.FromInt(2), fpdecimal.FromInt(100), "", ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if done.GetTradeOrder("a") == nil || done.GetTradeOrder("d") == nil || !done.Processed.Equal(fpdecimal.FromInt(2)) || done.Stored {
+		t.Fatal("order after canceled group member is skipped", done)
+	}
+
+	if len(done.Canceled) != 1 || done.Canceled[0] != "b" || ob.GetOrder("c") == nil || len(ob.Depth().Bid) != 0 {
+		t.Fatal("Wrong orderbook state", done.Canceled)
+	}
+}
+
+func TestOCOGroupPolicyProcess(t *testing.T) {
+	ob := matchingo.NewOrderBook()
+	ob.SetGroupPolicy("partial", true, false)
+	ob.SetGroupPolicy("cancel", false, true)
+
+	ob.Process(matchingo.NewLimitOrder("p-1", matchingo.Sell, fpdecimal.FromInt(2), fpdecimal.FromInt(100), "", "").SetGroup("partial"))
+	ob.Process(matchingo.NewLimitOrder("p-2", matchingo.Sell, fpdecimal.FromInt(2), fpdecimal.FromInt(110), "", "").SetGroup("partial"))
+
+	done, err := ob.Process(matchingo.NewLimitOrder("b1", matchingo.Buy, fpdecimal.FromInt(1), fpdecimal.FromInt(100), "", ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(done.Canceled) != 1 || done.Canceled[0] != "p-2" || ob.GetOrder("p-1") == nil {
+		t.Fatal("group is not triggered by partial fill")
+	}
+
+	ob.Process(matchingo.NewLimitOrder("c-1", matchingo.Sell, fpdecimal.FromInt(2), fpdecimal.FromInt(120), "", "").SetGroup("cancel"))
+	ob.Process(matchingo.NewLimitOrder("c-2", matchingo.Sell, fpdecimal.FromInt(2), fpdecimal.FromInt(130), "", "").SetGroup("cancel"))
+
+	if order, _ := ob.CancelOrder("c-1"); order == nil {
+		t.Fatal("order is not canceled")
+	}
+
+	if ob.GetOrder("c-2") != nil {
+		t.Fatal("group is not canceled")
+	}
+}
+
 func TestMarketProcess(t *testing.T) {
 	ob := matchingo.NewOrderBook()
 	addDepth(ob, "", fpdecimal.FromInt(2))
@@ -341,6 +656,159 @@ func TestMarketProcess(t *testing.T) {
 	}
 }
 
+func TestMarketProtectionProcess(t *testing.T) {
+	tests := []struct {
+		name      string
+		order     *matchingo.Order
+		processed fpdecimal.Decimal
+	}{
+		{
+			name:      "price",
+			order:     matchingo.NewMarketOrder("order", matchingo.Sell, fpdecimal.FromInt(10)).SetProtectionPrice(fpdecimal.FromInt(75)),
+			processed: fpdecimal.FromInt(4),
+		},
+		{
+			name:      "percent",
+			order:     matchingo.NewMarketOrder("order", matchingo.Buy, fpdecimal.FromInt(10)).SetProtectionPercent(fpdecimal.FromInt(10)),
+			processed: fpdecimal.FromInt(4),
+		},
+		{
+			name:      "levels",
+			order:     matchingo.NewMarketOrder("order", matchingo.Buy, fpdecimal.FromInt(10)).SetProtectionLevels(1),
+			processed: fpdecimal.FromInt(2),
+		},
+		{
+			name:      "quote",
+			order:     matchingo.NewMarketQuoteOrder("order", matchingo.Buy, fpdecimal.FromInt(1000)).SetProtectionPrice(fpdecimal.FromInt(110)),
+			processed: fpdecimal.FromIntScaled(420080), // 2 at 100 and 2 at 110 with Quote conversion truncation
+		},
+		{
+			name:      "no fills",
+			order:     matchingo.NewMarketOrder("order", matchingo.Buy, fpdecimal.FromInt(10)).SetProtectionPrice(fpdecimal.FromInt(95)),
+			processed: fpdecimal.Zero,
+		},
+	}
+
+	for _, tt := range tests {
+		ob := matchingo.NewOrderBook()
+		addDepth(ob, "", fpdecimal.FromInt(2))
+
+		done, err := ob.Process(tt.order)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !done.Processed.Equal(tt.processed) {
+			t.Fatal("Wrong processed", tt.name, done.Processed)
+		}
+
+		if !done.Left.Equal(tt.order.OriginalQty().Sub(tt.processed)) {
+			t.Fatal("Wrong left", tt.name, done.Left)
+		}
+
+		if len(done.Canceled) != 1 || !done.Order.IsCanceled() {
+			t.Fatal("remainder is not canceled", tt.name)
+		}
+	}
+}
+
+func TestMarketFOKProcess(t *testing.T) {
+	tests := []struct {
+		name   string
+		order  *matchingo.Order
+		filled bool
+	}{
+		{
+			name:   "base",
+			order:  matchingo.NewMarketOrder("order", matchingo.Buy, fpdecimal.FromInt(10)).SetTIF(matchingo.FOK),
+			filled: true,
+		},
+		{
+			name:   "base insufficient",
+			order:  matchingo.NewMarketOrder("order", matchingo.Sell, fpdecimal.FromInt(11)).SetTIF(matchingo.FOK),
+			filled: false,
+		},
+		{
+			name:   "quote",
+			order:  matchingo.NewMarketQuoteOrder("order", matchingo.Buy, fpdecimal.FromInt(1000)).SetTIF(matchingo.FOK),
+			filled: true,
+		},
+		{
+			name:   "quote insufficient",
+			order:  matchingo.NewMarketQuoteOrder("order", matchingo.Buy, fpdecimal.FromInt(1300)).SetTIF(matchingo.FOK),
+			filled: false,
+		},
+		{
+			name:   "protection",
+			order:  matchingo.NewMarketOrder("order", matchingo.Buy, fpdecimal.FromInt(5)).SetTIF(matchingo.FOK).SetProtectionLevels(2),
+			filled: false,
+		},
+	}
+
+	for _, tt := range tests {
+		ob := matchingo.NewOrderBook()
+		addDepth(ob, "", fpdecimal.FromInt(2))
+
+		done, err := ob.Process(tt.order)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if tt.filled {
+			if !done.Left.Equal(fpdecimal.Zero) || !done.Processed.Equal(tt.order.OriginalQty()) || len(done.Canceled) != 0 {
+				t.Fatal("FOK order is not filled", tt.name, done.Processed)
+			}
+			continue
+		}
+
+		if len(done.Trades) != 0 || !done.Processed.Equal(fpdecimal.Zero) || !done.Left.Equal(tt.order.OriginalQty()) {
+			t.Fatal("FOK order is executed partially", tt.name)
+		}
+
+		if len(done.Canceled) != 1 || !done.Order.IsCanceled() {
+			t.Fatal("FOK order is not canceled", tt.name)
+		}
+
+		if len(ob.Depth().Ask) != 5 || len(ob.Depth().Bid) != 5 {
+			t.Fatal("order book is changed", tt.name)
+		}
+	}
+}
+
+func TestMarketMinQtyProcess(t *testing.T) {
+	tests := []struct {
+		name  string
+		order *matchingo.Order
+	}{
+		{
+			name:  "market",
+			order: matchingo.NewMarketOrder("order", matchingo.Buy, fpdecimal.FromInt(5)),
+		},
+		{
+			name:  "market FOK",
+			order: matchingo.NewMarketOrder("order", matchingo.Buy, fpdecimal.FromInt(5)).SetTIF(matchingo.FOK),
+		},
+	}
+
+	for _, tt := range tests {
+		ob := matchingo.NewOrderBook()
+		ob.Process(matchingo.NewLimitOrder("resting", matchingo.Sell, fpdecimal.FromInt(10), fpdecimal.FromInt(100), "", "").SetMinQty(fpdecimal.FromInt(10)))
+
+		done, err := ob.Process(tt.order)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(done.Trades) != 0 || !done.Left.Equal(fpdecimal.FromInt(5)) {
+			t.Fatal("Wrong left quantity", tt.name, done.Left)
+		}
+
+		if len(done.Canceled) != 1 || !done.Order.IsCanceled() {
+			t.Fatal("market order is not canceled", tt.name)
+		}
+	}
+}
+
 func TestStopOrderProcess(t *testing.T) {
 	ob := matchingo.NewOrderBook()
 
@@ -368,6 +836,772 @@ func TestStopOrderProcess(t *testing.T) {
 	}
 }
 
+func TestStopTriggerProcess(t *testing.T) {
+	rise := matchingo.NewLimitOrder("order-rise", matchingo.Buy, fpdecimal.FromInt(2), fpdecimal.FromInt(100), "", "")
+	fall := matchingo.NewLimitOrder("order-fall", matchingo.Sell, fpdecimal.FromInt(2), fpdecimal.FromInt(90), "", "")
+
+	tests := []struct {
+		name    string
+		order   *matchingo.Order
+		trigger matchingo.Trigger
+		skip    *matchingo.Order
+		fire    *matchingo.Order
+	}{
+		{
+			name:    "buy stop-loss",
+			order:   matchingo.NewStopLimitOrder("stop", matchingo.Buy, fpdecimal.FromInt(1), fpdecimal.FromInt(100), fpdecimal.FromInt(100), ""),
+			trigger: matchingo.TriggerAbove,
+			skip:    fall,
+			fire:    rise,
+		},
+		{
+			name:    "sell stop-loss",
+			order:   matchingo.NewStopLimitOrder("stop", matchingo.Sell, fpdecimal.FromInt(1), fpdecimal.FromInt(90), fpdecimal.FromInt(90), ""),
+			trigger: matchingo.TriggerBelow,
+			skip:    rise,
+			fire:    fall,
+		},
+		{
+			name:    "buy take-profit",
+			order:   matchingo.NewTakeProfitLimitOrder("stop", matchingo.Buy, fpdecimal.FromInt(1), fpdecimal.FromInt(90), fpdecimal.FromInt(90), ""),
+			trigger: matchingo.TriggerBelow,
+			skip:    rise,
+			fire:    fall,
+		},
+		{
+			name:    "sell take-profit",
+			order:   matchingo.NewTakeProfitLimitOrder("stop", matchingo.Sell, fpdecimal.FromInt(1), fpdecimal.FromInt(100), fpdecimal.FromInt(100), ""),
+			trigger: matchingo.TriggerAbove,
+			skip:    fall,
+			fire:    rise,
+		},
+	}
+
+	for _, tt := range tests {
+		ob := matchingo.NewOrderBook()
+		addDepth(ob, "", fpdecimal.FromInt(2))
+
+		if tt.order.Trigger() != tt.trigger {
+			t.Fatal("Wrong trigger", tt.name)
+		}
+
+		ob.Process(tt.order)
+
+		done, err := ob.Process(matchingo.NewLimitOrder(tt.skip.ID(), tt.skip.Side(), tt.skip.Quantity(), tt.skip.Price(), "", ""))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(done.Activated) != 0 || ob.Stop.Len() != 1 {
+			t.Fatal("Wrong activated", tt.name)
+		}
+
+		done, err = ob.Process(matchingo.NewLimitOrder(tt.fire.ID(), tt.fire.Side(), tt.fire.Quantity(), tt.fire.Price(), "", ""))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(done.Activated) != 1 || done.Activated[0] != "stop" || ob.Stop.Len() != 0 {
+			t.Fatal("Wrong activated", tt.name)
+		}
+	}
+}
+
+func TestStopGapProcess(t *testing.T) {
+	ob := matchingo.NewOrderBook()
+
+	ob.Process(matchingo.NewLimitOrder("sell-100", matchingo.Sell, fpdecimal.FromInt(1), fpdecimal.FromInt(100), "", ""))
+	ob.Process(matchingo.NewLimitOrder("sell-103", matchingo.Sell, fpdecimal.FromInt(1), fpdecimal.FromInt(103), "", ""))
+	ob.Process(matchingo.NewStopLimitOrder("stop", matchingo.Buy, fpdecimal.FromInt(1), fpdecimal.FromInt(90), fpdecimal.FromFloat(101.5), ""))
+
+	done, err := ob.Process(matchingo.NewLimitOrder("order-buy", matchingo.Buy, fpdecimal.FromInt(2), fpdecimal.FromInt(103), "", ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(done.Activated) != 1 || done.Activated[0] != "stop" {
+		t.Fatal("Wrong activated")
+	}
+
+	if ob.Stop.Len() != 0 || ob.GetOrder("stop") == nil {
+		t.Fatal("stop book is broken")
+	}
+}
+
+func TestStopCascadeProcess(t *testing.T) {
+	ob := matchingo.NewOrderBook()
+	addDepth(ob, "", fpdecimal.FromInt(2))
+
+	ob.Process(matchingo.NewStopLimitOrder("stop-1", matchingo.Sell, fpdecimal.FromInt(3), fpdecimal.FromInt(80), fpdecimal.FromInt(90), ""))
+	ob.Process(matchingo.NewStopLimitOrder("stop-2", matchingo.Sell, fpdecimal.FromInt(1), fpdecimal.FromInt(70), fpdecimal.FromInt(80), ""))
+
+	done, err := ob.Process(matchingo.NewLimitOrder("order-s90", matchingo.Sell, fpdecimal.FromInt(1), fpdecimal.FromInt(90), "", ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(done.Activated) != 1 || done.Activated[0] != "stop-1" {
+		t.Fatal("Wrong activated")
+	}
+
+	if len(done.Triggered) != 2 {
+		t.Fatal("Wrong triggered")
+	}
+
+	first := done.Triggered[0]
+	if first.Order.ID() != "stop-1" || first.Stored || !first.Processed.Equal(fpdecimal.FromInt(3)) {
+		t.Fatal("activated marketable STOP-LIMIT is not matched")
+	}
+
+	if first.GetTradeOrder("buy-90") == nil || first.GetTradeOrder("buy-80") == nil {
+		t.Fatal("Wrong trades")
+	}
+
+	if len(first.Activated) != 1 || first.Activated[0] != "stop-2" {
+		t.Fatal("Wrong cascade")
+	}
+
+	second := done.Triggered[1]
+	if second.Order.ID() != "stop-2" || second.GetTradeOrder("buy-70") == nil {
+		t.Fatal("Wrong cascade trades")
+	}
+
+	if ob.Stop.Len() != 0 || ob.GetOrder("stop-1") != nil || ob.GetOrder("stop-2") != nil {
+		t.Fatal("stop book is broken")
+	}
+
+	if !ob.LastPrice().Equal(fpdecimal.FromInt(70)) {
+		t.Fatal("Wrong last price", ob.LastPrice())
+	}
+}
+
+func TestStopPlacementProcess(t *testing.T) {
+	ob := matchingo.NewOrderBook()
+	addDepth(ob, "", fpdecimal.FromInt(2))
+
+	done, err := ob.Process(matchingo.NewStopLimitOrder("stop", matchingo.Buy, fpdecimal.FromInt(1), fpdecimal.FromInt(110), fpdecimal.FromInt(120), ""))
+	if err != nil || !done.Stored {
+		t.Fatal("stop order is not stored")
+	}
+
+	_, err = ob.Process(matchingo.NewStopLimitOrder("stop", matchingo.Buy, fpdecimal.FromInt(1), fpdecimal.FromInt(110), fpdecimal.FromInt(120), ""))
+	if err != matchingo.ErrOrderExists {
+		t.Fatal("Wrong error", err)
+	}
+
+	_, err = ob.Process(matchingo.NewStopMarketOrder("buy-90", matchingo.Sell, fpdecimal.FromInt(1), fpdecimal.FromInt(80), ""))
+	if err != matchingo.ErrOrderExists {
+		t.Fatal("Wrong error", err)
+	}
+
+	ob.Process(matchingo.NewLimitOrder("order-s90", matchingo.Sell, fpdecimal.FromInt(1), fpdecimal.FromInt(90), "", ""))
+
+	// last Price is 90, sell stop at 95 is already triggered
+	done, err = ob.Process(matchingo.NewStopMarketOrder("stop-immediate", matchingo.Sell, fpdecimal.FromInt(2), fpdecimal.FromInt(95), ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(done.Activated) != 1 || done.Activated[0] != "stop-immediate" || done.Stored {
+		t.Fatal("Wrong activated")
+	}
+
+	if len(done.Triggered) != 1 || !done.Triggered[0].Processed.Equal(fpdecimal.FromInt(2)) {
+		t.Fatal("Wrong triggered")
+	}
+
+	ob.SetStopPolicy(matchingo.StopReject)
+
+	done, err = ob.Process(matchingo.NewStopMarketOrder("stop-rejected", matchingo.Sell, fpdecimal.FromInt(2), fpdecimal.FromInt(95), ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(done.Canceled) != 1 || !done.Order.IsCanceled() || len(done.Activated) != 0 || len(done.Triggered) != 0 {
+		t.Fatal("stop order is not rejected")
+	}
+
+	if ob.GetOrder("stop-rejected") != nil || ob.Stop.Len() != 1 {
+		t.Fatal("stop book is broken")
+	}
+}
+
+func TestStopTIFProcess(t *testing.T) {
+	ob := matchingo.NewOrderBook()
+	addDepth(ob, "", fpdecimal.FromInt(2))
+
+	ob.Process(matchingo.NewStopLimitOrder("stop-ioc", matchingo.Sell, fpdecimal.FromInt(3), fpdecimal.FromInt(90), fpdecimal.FromInt(90), "").SetTIF(matchingo.IOC))
+	ob.Process(matchingo.NewStopLimitOrder("stop-fok", matchingo.Sell, fpdecimal.FromInt(5), fpdecimal.FromInt(80), fpdecimal.FromInt(90), "").SetTIF(matchingo.FOK))
+	ob.Process(matchingo.NewStopLimitOrder("stop-gtc", matchingo.Sell, fpdecimal.FromInt(1), fpdecimal.FromInt(95), fpdecimal.FromInt(90), "").SetTIF(matchingo.GTC))
+
+	done, err := ob.Process(matchingo.NewLimitOrder("order-s90", matchingo.Sell, fpdecimal.FromInt(1), fpdecimal.FromInt(90), "", ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(done.Activated) != 3 || len(done.Triggered) != 3 {
+		t.Fatal("Wrong activated")
+	}
+
+	ioc := done.Triggered[0]
+	if ioc.Order.ID() != "stop-ioc" || !ioc.Processed.Equal(fpdecimal.FromInt(1)) || !ioc.Order.IsCanceled() {
+		t.Fatal("activated IOC is not canceled")
+	}
+
+	fok := done.Triggered[1]
+	if fok.Order.ID() != "stop-fok" || !fok.Processed.Equal(fpdecimal.Zero) || !fok.Order.IsCanceled() {
+		t.Fatal("activated FOK is not canceled")
+	}
+
+	gtc := done.Triggered[2]
+	if gtc.Order.ID() != "stop-gtc" || !gtc.Stored {
+		t.Fatal("activated GTC is not stored")
+	}
+
+	if ob.GetOrder("stop-ioc") != nil || ob.GetOrder("stop-fok") != nil || ob.GetOrder("stop-gtc") == nil {
+		t.Fatal("Wrong order book")
+	}
+}
+
+func TestStopMarketOrderProcess(t *testing.T) {
+	ob := matchingo.NewOrderBook()
+	addDepth(ob, "", fpdecimal.FromInt(2))
+
+	ob.Process(matchingo.NewStopMarketOrder("stop-market", matchingo.Sell, fpdecimal.FromInt(3), fpdecimal.FromInt(90), ""))
+
+	if ob.Stop.Len() != 1 {
+		t.Fatal("stop book is broken")
+	}
+
+	done, err := ob.Process(matchingo.NewLimitOrder("order-s90", matchingo.Sell, fpdecimal.FromInt(2), fpdecimal.FromInt(90), "", ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if ob.Stop.Len() != 0 {
+		t.Fatal("stop book is broken")
+	}
+
+	if len(done.Activated) != 1 || done.Activated[0] != "stop-market" {
+		t.Fatal("Wrong activated")
+	}
+
+	if len(done.Triggered) != 1 {
+		t.Fatal("Wrong triggered")
+	}
+
+	triggered := done.Triggered[0]
+
+	if !triggered.Order.IsMarketOrder() {
+		t.Fatal("Wrong activated order type")
+	}
+
+	if !triggered.Processed.Equal(fpdecimal.FromInt(3)) {
+		t.Fatal("Wrong quantity processed", triggered.Processed)
+	}
+
+	if triggered.GetTradeOrder("buy-80") == nil || triggered.GetTradeOrder("buy-70") == nil {
+		t.Fatal("Wrong trades")
+	}
+
+	if ob.GetOrder("stop-market") != nil {
+		t.Fatal("market order can't rest in order book")
+	}
+}
+
+func TestTrailingStopOrderProcess(t *testing.T) {
+	ob := matchingo.NewOrderBook()
+
+	ob.Process(matchingo.NewLimitOrder("order-s100", matchingo.Sell, fpdecimal.FromInt(1), fpdecimal.FromInt(100), "", ""))
+	ob.Process(matchingo.NewLimitOrder("order-b100", matchingo.Buy, fpdecimal.FromInt(1), fpdecimal.FromInt(100), "", ""))
+
+	if !ob.LastPrice().Equal(fpdecimal.FromInt(100)) {
+		t.Fatal("Wrong last price")
+	}
+
+	trailing := matchingo.NewTrailingStopMarketOrder("trailing", matchingo.Sell, fpdecimal.FromInt(1), fpdecimal.FromInt(80), fpdecimal.FromInt(10), matchingo.TrailAbsolute, "")
+	ob.Process(trailing)
+
+	if !trailing.StopPrice().Equal(fpdecimal.FromInt(90)) {
+		t.Fatal("Wrong stop price on placement", trailing.StopPrice())
+	}
+
+	ob.Process(matchingo.NewLimitOrder("order-s110", matchingo.Sell, fpdecimal.FromInt(1), fpdecimal.FromInt(110), "", ""))
+	ob.Process(matchingo.NewLimitOrder("order-b110", matchingo.Buy, fpdecimal.FromInt(1), fpdecimal.FromInt(110), "", ""))
+
+	if !trailing.StopPrice().Equal(fpdecimal.FromInt(100)) {
+		t.Fatal("Wrong trailed stop price", trailing.StopPrice())
+	}
+
+	ob.Process(matchingo.NewLimitOrder("order-b95", matchingo.Buy, fpdecimal.FromInt(1), fpdecimal.FromInt(95), "", ""))
+	ob.Process(matchingo.NewLimitOrder("order-b100-2", matchingo.Buy, fpdecimal.FromInt(1), fpdecimal.FromInt(100), "", ""))
+	done, err := ob.Process(matchingo.NewLimitOrder("order-s100-2", matchingo.Sell, fpdecimal.FromInt(1), fpdecimal.FromInt(100), "", ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(done.Activated) != 1 || done.Activated[0] != "trailing" {
+		t.Fatal("Wrong activated")
+	}
+
+	if len(done.Triggered) != 1 || done.Triggered[0].GetTradeOrder("order-b95") == nil {
+		t.Fatal("Wrong triggered")
+	}
+
+	if ob.Stop.Len() != 0 {
+		t.Fatal("stop book is broken")
+	}
+}
+
+func TestIcebergOrderProcess(t *testing.T) {
+	ob := matchingo.NewOrderBook()
+
+	done, err := ob.Process(matchingo.NewIcebergOrder("iceberg", matchingo.Sell, fpdecimal.FromInt(10), fpdecimal.FromInt(3), fpdecimal.FromInt(100), "", ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !done.Stored {
+		t.Fatal("Wrong stored")
+	}
+
+	ob.Process(matchingo.NewLimitOrder("order-s100", matchingo.Sell, fpdecimal.FromInt(2), fpdecimal.FromInt(100), "", ""))
+
+	if ob.Depth().Ask["100.000"] != "5.000" {
+		t.Fatal("Wrong visible volume", ob.DepthJSON())
+	}
+
+	done, err = ob.Process(matchingo.NewLimitOrder("order-b100", matchingo.Buy, fpdecimal.FromInt(4), fpdecimal.FromInt(100), "", ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !done.GetTradeOrder("iceberg").Quantity.Equal(fpdecimal.FromInt(3)) {
+		t.Fatal("Wrong iceberg trade quantity")
+	}
+
+	// refreshed iceberg lost time priority
+	if !done.GetTradeOrder("order-s100").Quantity.Equal(fpdecimal.FromInt(1)) {
+		t.Fatal("Wrong trade quantity")
+	}
+
+	iceberg := ob.GetOrder("iceberg")
+	if !iceberg.Quantity().Equal(fpdecimal.FromInt(3)) || !iceberg.Reserve().Equal(fpdecimal.FromInt(4)) {
+		t.Fatal("Wrong iceberg refresh", iceberg.Quantity(), iceberg.Reserve())
+	}
+
+	if ob.Depth().Ask["100.000"] != "4.000" {
+		t.Fatal("Wrong visible volume", ob.DepthJSON())
+	}
+
+	done, err = ob.Process(matchingo.NewMarketOrder("order-market", matchingo.Buy, fpdecimal.FromInt(20)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !done.Processed.Equal(fpdecimal.FromInt(8)) {
+		t.Fatal("Wrong quantity processed", done.Processed)
+	}
+
+	if ob.GetOrder("iceberg") != nil {
+		t.Fatal("iceberg order is not filled")
+	}
+}
+
+func TestIcebergFOKProcess(t *testing.T) {
+	ob := matchingo.NewOrderBook()
+
+	ob.Process(matchingo.NewIcebergOrder("iceberg", matchingo.Sell, fpdecimal.FromInt(100), fpdecimal.FromInt(10), fpdecimal.FromInt(100), "", ""))
+
+	done, err := ob.Process(matchingo.NewLimitOrder("order-fok", matchingo.Buy, fpdecimal.FromInt(50), fpdecimal.FromInt(100), matchingo.FOK, ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if done.Order.IsCanceled() || !done.Processed.Equal(fpdecimal.FromInt(50)) {
+		t.Fatal("FOK order doesn't count iceberg reserve", done.Processed)
+	}
+}
+
+func TestPostOnlyProcess(t *testing.T) {
+	ob := matchingo.NewOrderBook()
+	addDepth(ob, "", fpdecimal.FromInt(2))
+
+	done, err := ob.Process(matchingo.NewLimitOrder("post-only-reject", matchingo.Buy, fpdecimal.FromInt(1), fpdecimal.FromInt(100), "", "").SetPostOnly(matchingo.PostOnlyReject))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if done.Stored || len(done.Trades) != 0 {
+		t.Fatal("post-only order took liquidity")
+	}
+
+	if len(done.Canceled) != 1 || !done.Order.IsCanceled() {
+		t.Fatal("Wrong canceled")
+	}
+
+	ob.SetTickSize(fpdecimal.FromFloat(0.5))
+
+	done, err = ob.Process(matchingo.NewLimitOrder("post-only-slide", matchingo.Sell, fpdecimal.FromInt(1), fpdecimal.FromInt(80), "", "").SetPostOnly(matchingo.PostOnlySlide))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !done.Stored || len(done.Trades) != 0 {
+		t.Fatal("post-only order took liquidity")
+	}
+
+	if len(done.Repriced) != 1 || !done.Order.Price().Equal(fpdecimal.FromFloat(90.5)) {
+		t.Fatal("Wrong slide", done.Order.Price())
+	}
+
+	done, err = ob.Process(matchingo.NewLimitOrder("post-only-maker", matchingo.Sell, fpdecimal.FromInt(1), fpdecimal.FromInt(150), "", "").SetPostOnly(matchingo.PostOnlyReject))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !done.Stored || len(done.Repriced) != 0 {
+		t.Fatal("Wrong post-only maker order")
+	}
+}
+
+func TestMarketToLimitProcess(t *testing.T) {
+	ob := matchingo.NewOrderBook()
+
+	done, err := ob.Process(matchingo.NewMarketToLimitOrder("order-empty", matchingo.Buy, fpdecimal.FromInt(1)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if done.Stored || !done.Order.IsCanceled() || len(done.Canceled) != 1 {
+		t.Fatal("market-to-limit order without fills must be canceled")
+	}
+
+	ob.Process(matchingo.NewLimitOrder("order-s100", matchingo.Sell, fpdecimal.FromInt(2), fpdecimal.FromInt(100), "", ""))
+	ob.Process(matchingo.NewLimitOrder("order-s110", matchingo.Sell, fpdecimal.FromInt(2), fpdecimal.FromInt(110), "", ""))
+
+	done, err = ob.Process(matchingo.NewMarketToLimitOrder("order-mtl", matchingo.Buy, fpdecimal.FromInt(5)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(done.Trades) != 3 || !done.Processed.Equal(fpdecimal.FromInt(4)) || !done.Left.Equal(fpdecimal.FromInt(1)) {
+		t.Fatal("Wrong fills", done)
+	}
+
+	if !done.Stored || len(done.Canceled) != 0 {
+		t.Fatal("Wrong stored")
+	}
+
+	order := ob.GetOrder("order-mtl")
+	if order == nil || !order.IsLimitOrder() || !order.Price().Equal(fpdecimal.FromInt(110)) || !order.Quantity().Equal(fpdecimal.FromInt(1)) {
+		t.Fatal("Wrong stored remainder")
+	}
+
+	if ob.Depth().Bid["110.000"] != "1.000" {
+		t.Fatal("Wrong depth", ob.DepthJSON())
+	}
+
+	if _, err := ob.Process(matchingo.NewMarketToLimitOrder("order-mtl", matchingo.Buy, fpdecimal.FromInt(5))); err == nil {
+		t.Fatal("Can add existing order")
+	}
+}
+
+func TestLimitQuoteProcess(t *testing.T) {
+	ob := matchingo.NewOrderBook()
+
+	ob.Process(matchingo.NewLimitOrder("order-s20", matchingo.Sell, fpdecimal.FromInt(10), fpdecimal.FromInt(20), "", ""))
+	ob.Process(matchingo.NewLimitOrder("order-s25", matchingo.Sell, fpdecimal.FromInt(10), fpdecimal.FromInt(25), "", ""))
+	ob.Process(matchingo.NewLimitOrder("order-s30", matchingo.Sell, fpdecimal.FromInt(10), fpdecimal.FromInt(30), "", ""))
+
+	done, err := ob.Process(matchingo.NewLimitQuoteOrder("order-quote", matchingo.Buy, fpdecimal.FromInt(1000), fpdecimal.FromInt(25), matchingo.FOK, ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !done.Order.IsCanceled() || len(done.Trades) != 0 {
+		t.Fatal("Wrong FOK quote order")
+	}
+
+	done, err = ob.Process(matchingo.NewLimitQuoteOrder("order-b25", matchingo.Buy, fpdecimal.FromInt(1000), fpdecimal.FromInt(25), "", ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(done.Trades) != 3 || !done.Processed.Equal(fpdecimal.FromInt(450)) || !done.Left.Equal(fpdecimal.FromInt(550)) {
+		t.Fatal("Wrong fills", done)
+	}
+
+	order := ob.GetOrder("order-b25")
+	if !done.Stored || order.IsQuote() || !order.Quantity().Equal(fpdecimal.FromInt(22)) {
+		t.Fatal("Wrong stored remainder", order)
+	}
+
+	ob.SetQuoteRounding(matchingo.RoundUp)
+
+	done, err = ob.Process(matchingo.NewLimitQuoteOrder("order-s30-up", matchingo.Sell, fpdecimal.FromInt(100), fpdecimal.FromInt(30), "", ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !ob.GetOrder("order-s30-up").Quantity().Equal(fpdecimal.FromIntScaled(3334)) {
+		t.Fatal("Wrong rounding", ob.GetOrder("order-s30-up").Quantity())
+	}
+
+	ob.SetQuoteRounding(matchingo.RoundHalfUp)
+
+	done, err = ob.Process(matchingo.NewLimitQuoteOrder("order-s30-half", matchingo.Sell, fpdecimal.FromInt(200), fpdecimal.FromInt(30), "", ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !ob.GetOrder("order-s30-half").Quantity().Equal(fpdecimal.FromIntScaled(6667)) {
+		t.Fatal("Wrong rounding", ob.GetOrder("order-s30-half").Quantity())
+	}
+}
+
+func TestMinQtyProcess(t *testing.T) {
+	ob := matchingo.NewOrderBook()
+	addDepth(ob, "", fpdecimal.FromInt(2))
+
+	done, err := ob.Process(matchingo.NewLimitOrder("order-ioc", matchingo.Buy, fpdecimal.FromInt(6), fpdecimal.FromInt(110), matchingo.IOC, "").SetMinQty(fpdecimal.FromInt(5)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(done.Trades) != 0 || !done.Order.IsCanceled() {
+		t.Fatal("order with minimal quantity is executed partially")
+	}
+
+	done, err = ob.Process(matchingo.NewLimitOrder("order-ioc-2", matchingo.Buy, fpdecimal.FromInt(6), fpdecimal.FromInt(120), matchingo.IOC, "").SetMinQty(fpdecimal.FromInt(5)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !done.Processed.Equal(fpdecimal.FromInt(6)) {
+		t.Fatal("Wrong quantity processed", done.Processed)
+	}
+
+	ob.Process(matchingo.NewLimitOrder("resting", matchingo.Sell, fpdecimal.FromInt(10), fpdecimal.FromInt(125), "", "").SetMinQty(fpdecimal.FromInt(5)))
+	ob.Process(matchingo.NewLimitOrder("order-s125", matchingo.Sell, fpdecimal.FromInt(2), fpdecimal.FromInt(125), "", ""))
+
+	done, err = ob.Process(matchingo.NewLimitOrder("order-small", matchingo.Buy, fpdecimal.FromInt(3), fpdecimal.FromInt(125), matchingo.IOC, ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if done.GetTradeOrder("resting") != nil || !done.GetTradeOrder("order-s125").Quantity.Equal(fpdecimal.FromInt(2)) {
+		t.Fatal("resting order with minimal quantity is matched with small order")
+	}
+
+	done, err = ob.Process(matchingo.NewLimitOrder("order-big", matchingo.Buy, fpdecimal.FromInt(5), fpdecimal.FromInt(125), "", ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !done.GetTradeOrder("resting").Quantity.Equal(fpdecimal.FromInt(5)) {
+		t.Fatal("resting order with minimal quantity is not matched")
+	}
+}
+
+func TestMinQtyQuoteProcess(t *testing.T) {
+	ob := matchingo.NewOrderBook()
+
+	done, err := ob.Process(matchingo.NewLimitQuoteOrder("order-quote", matchingo.Buy, fpdecimal.FromInt(1000), fpdecimal.FromInt(10), "", "").SetMinQty(fpdecimal.FromInt(500)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !done.Stored || !ob.GetOrder("order-quote").Quantity().Equal(fpdecimal.FromInt(100)) || !ob.GetOrder("order-quote").MinQty().Equal(fpdecimal.FromInt(50)) {
+		t.Fatal("Wrong resting quote order", ob.GetOrder("order-quote").MinQty())
+	}
+
+	done, err = ob.Process(matchingo.NewLimitOrder("order-s10", matchingo.Sell, fpdecimal.FromInt(60), fpdecimal.FromInt(10), "", ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if done.Stored || !done.GetTradeOrder("order-quote").Quantity.Equal(fpdecimal.FromInt(60)) {
+		t.Fatal("resting quote order with minimal quantity is not matched", done)
+	}
+}
+
+func TestMinQtyGTCProcess(t *testing.T) {
+	ob := matchingo.NewOrderBook()
+
+	ob.Process(matchingo.NewLimitOrder("order-s100", matchingo.Sell, fpdecimal.FromInt(1), fpdecimal.FromInt(100), "", ""))
+
+	// crossing Order which minimal quantity can't be met is canceled, it never rests at crossing Price
+	done, err := ob.Process(matchingo.NewLimitOrder("order-crossing", matchingo.Buy, fpdecimal.FromInt(10), fpdecimal.FromInt(101), matchingo.GTC, "").SetMinQty(fpdecimal.FromInt(5)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if done.Stored || len(done.Trades) != 0 || !done.Order.IsCanceled() || len(ob.Depth().Bid) != 0 {
+		t.Fatal("crossing GTC order with minimal quantity is stored", ob.DepthJSON())
+	}
+
+	done, err = ob.Process(matchingo.NewLimitOrder("order-gtc", matchingo.Buy, fpdecimal.FromInt(6), fpdecimal.FromInt(99), matchingo.GTC, "").SetMinQty(fpdecimal.FromInt(5)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !done.Stored || !ob.GetOrder("order-gtc").MinQty().Equal(fpdecimal.FromInt(5)) {
+		t.Fatal("GTC order with minimal quantity is not stored", done)
+	}
+
+	done, err = ob.Process(matchingo.NewLimitOrder("order-s99-small", matchingo.Sell, fpdecimal.FromInt(1), fpdecimal.FromInt(99), matchingo.IOC, ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if done.GetTradeOrder("order-gtc") != nil {
+		t.Fatal("resting order with minimal quantity is matched with small order")
+	}
+
+	done, err = ob.Process(matchingo.NewLimitOrder("order-s99-big", matchingo.Sell, fpdecimal.FromInt(5), fpdecimal.FromInt(99), "", ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !done.GetTradeOrder("order-gtc").Quantity.Equal(fpdecimal.FromInt(5)) {
+		t.Fatal("resting order with minimal quantity is not matched")
+	}
+}
+
+func TestMinQtyFOKProcess(t *testing.T) {
+	ob := matchingo.NewOrderBook()
+
+	ob.Process(matchingo.NewLimitOrder("resting", matchingo.Sell, fpdecimal.FromInt(10), fpdecimal.FromInt(100), "", "").SetMinQty(fpdecimal.FromInt(10)))
+
+	done, err := ob.Process(matchingo.NewLimitOrder("order-fok", matchingo.Buy, fpdecimal.FromInt(5), fpdecimal.FromInt(100), matchingo.FOK, ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if done.Stored || len(done.Trades) != 0 || !done.Order.IsCanceled() {
+		t.Fatal("FOK order is not canceled", done)
+	}
+
+	if ob.GetOrder("order-fok") != nil || !ob.GetOrder("resting").Quantity().Equal(fpdecimal.FromInt(10)) {
+		t.Fatal("Wrong orderbook state")
+	}
+}
+
+func TestAllOrNoneProcess(t *testing.T) {
+	ob := matchingo.NewOrderBook()
+
+	ob.Process(matchingo.NewLimitOrder("aon", matchingo.Sell, fpdecimal.FromInt(10), fpdecimal.FromInt(100), "", "").SetAllOrNone())
+	ob.Process(matchingo.NewLimitOrder("order-s100", matchingo.Sell, fpdecimal.FromInt(2), fpdecimal.FromInt(100), "", ""))
+
+	depth := ob.Depth()
+	if depth.Ask["100.000"] != "2.000" || depth.AONAsk["100.000"] != "10.000" {
+		t.Fatal("Wrong depth", ob.DepthJSON())
+	}
+
+	done, err := ob.Process(matchingo.NewLimitOrder("order-b100", matchingo.Buy, fpdecimal.FromInt(5), fpdecimal.FromInt(100), matchingo.IOC, ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if done.GetTradeOrder("aon") != nil || !done.Processed.Equal(fpdecimal.FromInt(2)) {
+		t.Fatal("all-or-none order is filled partially")
+	}
+
+	done, err = ob.Process(matchingo.NewLimitOrder("order-b100-2", matchingo.Buy, fpdecimal.FromInt(12), fpdecimal.FromInt(100), "", ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !done.GetTradeOrder("aon").Quantity.Equal(fpdecimal.FromInt(10)) || !done.Left.Equal(fpdecimal.FromInt(2)) {
+		t.Fatal("all-or-none order is not filled", done)
+	}
+
+	done, err = ob.Process(matchingo.NewLimitOrder("aon-incoming", matchingo.Sell, fpdecimal.FromInt(5), fpdecimal.FromInt(90), "", "").SetAllOrNone())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(done.Trades) != 0 || !done.Stored {
+		t.Fatal("incoming all-or-none order is filled partially")
+	}
+
+	if ob.Depth().AONAsk["90.000"] != "5.000" {
+		t.Fatal("Wrong depth", ob.DepthJSON())
+	}
+}
+
+func TestAllOrNoneQuoteProcess(t *testing.T) {
+	ob := matchingo.NewOrderBook()
+
+	ob.Process(matchingo.NewLimitOrder("order-s10", matchingo.Sell, fpdecimal.FromInt(100), fpdecimal.FromInt(10), "", ""))
+
+	done, err := ob.Process(matchingo.NewLimitQuoteOrder("aon", matchingo.Buy, fpdecimal.FromInt(500), fpdecimal.FromInt(10), "", "").SetAllOrNone())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if done.Stored || !done.Processed.Equal(fpdecimal.FromInt(500)) || !done.GetTradeOrder("order-s10").Quantity.Equal(fpdecimal.FromInt(50)) {
+		t.Fatal("quote all-or-none order is not filled", done)
+	}
+}
+
+func TestAllOrNoneMinQtyProcess(t *testing.T) {
+	ob := matchingo.NewOrderBook()
+
+	ob.Process(matchingo.NewLimitOrder("order-s100", matchingo.Sell, fpdecimal.FromInt(5), fpdecimal.FromInt(100), "", ""))
+	ob.Process(matchingo.NewLimitOrder("order-s100-min", matchingo.Sell, fpdecimal.FromInt(5), fpdecimal.FromInt(100), "", "").SetMinQty(fpdecimal.FromInt(5)))
+
+	done, err := ob.Process(matchingo.NewLimitOrder("aon", matchingo.Buy, fpdecimal.FromInt(8), fpdecimal.FromInt(100), "", "").SetAllOrNone())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(done.Trades) != 0 || !done.Stored {
+		t.Fatal("all-or-none order is executed partially", done)
+	}
+
+	if !ob.GetOrder("aon").Quantity().Equal(fpdecimal.FromInt(8)) || !ob.GetOrder("order-s100").Quantity().Equal(fpdecimal.FromInt(5)) {
+		t.Fatal("Wrong orderbook state")
+	}
+}
+
+func TestHiddenProcess(t *testing.T) {
+	ob := matchingo.NewOrderBook()
+
+	ob.Process(matchingo.NewLimitOrder("hidden", matchingo.Sell, fpdecimal.FromInt(5), fpdecimal.FromInt(100), "", "").SetHidden())
+	ob.Process(matchingo.NewLimitOrder("hidden-only", matchingo.Sell, fpdecimal.FromInt(5), fpdecimal.FromInt(110), "", "").SetHidden())
+	ob.Process(matchingo.NewLimitOrder("displayed", matchingo.Sell, fpdecimal.FromInt(2), fpdecimal.FromInt(100), "", ""))
+
+	depth := ob.Depth()
+	if len(depth.Ask) != 1 || depth.Ask["100.000"] != "2.000" {
+		t.Fatal("Wrong depth", ob.DepthJSON())
+	}
+
+	if strings.Contains(ob.String(), "110.000") {
+		t.Fatal("hidden order is displayed", ob)
+	}
+
+	done, err := ob.Process(matchingo.NewLimitOrder("order-b100", matchingo.Buy, fpdecimal.FromInt(3), fpdecimal.FromInt(100), "", ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// displayed order has priority at the same price
+	if !done.GetTradeOrder("displayed").Quantity.Equal(fpdecimal.FromInt(2)) || !done.GetTradeOrder("hidden").Quantity.Equal(fpdecimal.FromInt(1)) {
+		t.Fatal("Wrong priority", done)
+	}
+
+	done, err = ob.Process(matchingo.NewLimitOrder("order-b110", matchingo.Buy, fpdecimal.FromInt(9), fpdecimal.FromInt(110), matchingo.FOK, ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !done.Processed.Equal(fpdecimal.FromInt(9)) {
+		t.Fatal("hidden liquidity is not fillable", done)
+	}
+}
+
 func TestPriceCalculation(t *testing.T) {
 	ob := matchingo.NewOrderBook()
 	addDepth(ob, "05-", fpdecimal.FromInt(10))
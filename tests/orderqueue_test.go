@@ -174,6 +174,28 @@ func TestOrderQueueUpdate(t *testing.T) {
 	}
 }
 
+func TestOrderQueueHidden(t *testing.T) {
+	price := fpdecimal.FromInt(100)
+	oq := matchingo.NewOrderQueue(price)
+
+	oq.Append(matchingo.NewLimitOrder("hidden", matchingo.Buy, fpdecimal.FromInt(10), price, "", "").SetHidden())
+	oq.Append(matchingo.NewLimitOrder("displayed", matchingo.Buy, fpdecimal.FromInt(20), price, "", ""))
+
+	if oq.First().ID() != "displayed" {
+		t.Fatalf("Invalid priority of hidden order")
+	}
+
+	if !oq.Volume().Equal(fpdecimal.FromInt(20)) || !oq.HiddenVolume().Equal(fpdecimal.FromInt(10)) || oq.HiddenLen() != 1 {
+		t.Fatalf("Invalid order volume (have: %s, hidden: %s)", oq.Volume(), oq.HiddenVolume())
+	}
+
+	oq.RemoveByID("hidden")
+
+	if !oq.HiddenVolume().Equal(fpdecimal.Zero) || oq.HiddenLen() != 0 {
+		t.Fatalf("Invalid hidden volume (have: %s, want: 0)", oq.HiddenVolume())
+	}
+}
+
 var BenchOrderQueue = matchingo.NewOrderQueue(BenchPrice)
 
 func BenchmarkOrderQueue(b *testing.B) {
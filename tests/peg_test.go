@@ -0,0 +1,142 @@
+package tests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gonevo/matchingo"
+	"github.com/nikolaydubina/fpdecimal"
+)
+
+func TestPeggedProcess(t *testing.T) {
+	ob := matchingo.NewOrderBook()
+
+	done, err := ob.Process(matchingo.NewPeggedOrder("peg-empty", matchingo.Buy, fpdecimal.FromInt(1), matchingo.PegPrimary, fpdecimal.Zero, fpdecimal.Zero, "", ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !done.Order.IsCanceled() || done.Stored {
+		t.Fatal("pegged order without reference price is stored")
+	}
+
+	ob.Process(matchingo.NewLimitOrder("order-s105", matchingo.Sell, fpdecimal.FromInt(2), fpdecimal.FromInt(105), "", ""))
+	ob.Process(matchingo.NewLimitOrder("order-b100", matchingo.Buy, fpdecimal.FromInt(2), fpdecimal.FromInt(100), "", ""))
+
+	ob.Process(matchingo.NewPeggedOrder("peg-primary", matchingo.Buy, fpdecimal.FromInt(1), matchingo.PegPrimary, fpdecimal.Zero, fpdecimal.Zero, "", ""))
+	ob.Process(matchingo.NewPeggedOrder("peg-mid", matchingo.Sell, fpdecimal.FromInt(1), matchingo.PegMidpoint, fpdecimal.Zero, fpdecimal.Zero, "", ""))
+	ob.Process(matchingo.NewPeggedOrder("peg-market", matchingo.Buy, fpdecimal.FromInt(1), matchingo.PegMarket, fpdecimal.FromInt(-1), fpdecimal.FromInt(101), "", ""))
+
+	if !ob.GetOrder("peg-primary").Price().Equal(fpdecimal.FromInt(100)) {
+		t.Fatal("Wrong primary peg price", ob.GetOrder("peg-primary").Price())
+	}
+
+	if !ob.GetOrder("peg-mid").Price().Equal(fpdecimal.FromFloat(102.5)) {
+		t.Fatal("Wrong midpoint peg price", ob.GetOrder("peg-mid").Price())
+	}
+
+	if !ob.GetOrder("peg-market").Price().Equal(fpdecimal.FromInt(101)) {
+		t.Fatal("Wrong market peg price", ob.GetOrder("peg-market").Price())
+	}
+
+	// pegged orders do not peg to themselves
+	done, err = ob.Process(matchingo.NewLimitOrder("order-b101", matchingo.Buy, fpdecimal.FromInt(1), fpdecimal.FromInt(101), "", ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(done.Repriced) != 2 || done.Repriced[0] != "peg-primary" || done.Repriced[1] != "peg-mid" {
+		t.Fatal("Wrong repriced", done.Repriced)
+	}
+
+	if !ob.GetOrder("peg-primary").Price().Equal(fpdecimal.FromInt(101)) || !ob.GetOrder("peg-mid").Price().Equal(fpdecimal.FromInt(103)) {
+		t.Fatal("Wrong repricing")
+	}
+
+	if ob.Depth().Bid["101.000"] != "3.000" {
+		t.Fatal("Wrong depth", ob.DepthJSON())
+	}
+
+	ob.CancelOrder("order-b101")
+
+	if !ob.GetOrder("peg-primary").Price().Equal(fpdecimal.FromInt(100)) {
+		t.Fatal("Wrong repricing on cancel", ob.GetOrder("peg-primary").Price())
+	}
+
+	done, err = ob.Process(matchingo.NewLimitOrder("order-s100", matchingo.Sell, fpdecimal.FromInt(2), fpdecimal.FromInt(100), "", ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// repriced order is queued after orders at the same price
+	if done.GetTradeOrder("peg-market") == nil || done.GetTradeOrder("order-b100") == nil || done.GetTradeOrder("peg-primary") != nil {
+		t.Fatal("Wrong priority", done)
+	}
+}
+
+func TestPeggedReferenceLost(t *testing.T) {
+	ob := matchingo.NewOrderBook()
+
+	ob.Process(matchingo.NewLimitOrder("order-s105", matchingo.Sell, fpdecimal.FromInt(2), fpdecimal.FromInt(105), "", ""))
+	ob.Process(matchingo.NewPeggedOrder("peg-primary", matchingo.Sell, fpdecimal.FromInt(1), matchingo.PegPrimary, fpdecimal.Zero, fpdecimal.Zero, "", ""))
+
+	done, err := ob.Process(matchingo.NewLimitOrder("order-b105", matchingo.Buy, fpdecimal.FromInt(2), fpdecimal.FromInt(105), "", ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(done.Canceled) != 1 || done.Canceled[0] != "peg-primary" {
+		t.Fatal("Wrong canceled", done.Canceled)
+	}
+
+	if ob.GetOrder("peg-primary") != nil || len(ob.Depth().Ask) != 0 {
+		t.Fatal("pegged order without reference price is stored")
+	}
+
+	ob.Process(matchingo.NewLimitOrder("order-b100", matchingo.Buy, fpdecimal.FromInt(2), fpdecimal.FromInt(100), "", "").SetOwner("alice"))
+	ob.Process(matchingo.NewPeggedOrder("peg-bid", matchingo.Buy, fpdecimal.FromInt(1), matchingo.PegPrimary, fpdecimal.Zero, fpdecimal.Zero, "", ""))
+
+	canceled := ob.CancelOrders(matchingo.NewCancelFilter().SetOwner("alice")).Canceled
+	if len(canceled) != 2 || canceled[0] != "order-b100" || canceled[1] != "peg-bid" {
+		t.Fatal("Wrong canceled", canceled)
+	}
+}
+
+func TestPeggedCancelReport(t *testing.T) {
+	clock := &testClock{now: time.Date(2023, 1, 1, 10, 0, 0, 0, time.UTC)}
+
+	ob := matchingo.NewOrderBook()
+	ob.SetClock(clock)
+
+	ob.Process(matchingo.NewLimitOrder("bid-100", matchingo.Buy, fpdecimal.FromInt(1), fpdecimal.FromInt(100), "", ""))
+	ob.Process(matchingo.NewLimitOrder("bid-99", matchingo.Buy, fpdecimal.FromInt(1), fpdecimal.FromInt(99), matchingo.DAY, ""))
+	ob.Process(matchingo.NewLimitOrder("bid-98", matchingo.Buy, fpdecimal.FromInt(1), fpdecimal.FromInt(98), "", "").SetExpiry(clock.now.Add(time.Hour)))
+	ob.Process(matchingo.NewPeggedOrder("peg", matchingo.Buy, fpdecimal.FromInt(1), matchingo.PegPrimary, fpdecimal.Zero, fpdecimal.Zero, "", ""))
+
+	order, cancellation := ob.CancelOrder("bid-100")
+	if order == nil || len(cancellation.Canceled) != 1 || len(cancellation.Repriced) != 1 || cancellation.Repriced[0] != "peg" {
+		t.Fatal("Wrong cancellation", cancellation)
+	}
+
+	cancellation = ob.CloseSession()
+	if len(cancellation.Canceled) != 1 || cancellation.Canceled[0] != "bid-99" || len(cancellation.Repriced) != 1 || cancellation.Repriced[0] != "peg" {
+		t.Fatal("Wrong cancellation", cancellation)
+	}
+
+	expiry := ob.ExpireOrders(clock.now.Add(2 * time.Hour))
+	if len(expiry.Expired) != 1 || expiry.Expired[0] != "bid-98" || len(expiry.Canceled) != 1 || expiry.Canceled[0] != "peg" {
+		t.Fatal("Wrong expiry", expiry)
+	}
+
+	if ob.GetOrder("peg") != nil {
+		t.Fatal("pegged order without reference price is stored")
+	}
+
+	ob.Process(matchingo.NewLimitOrder("bid", matchingo.Buy, fpdecimal.FromInt(1), fpdecimal.FromInt(100), "", ""))
+	ob.Process(matchingo.NewPeggedOrder("peg-2", matchingo.Buy, fpdecimal.FromInt(1), matchingo.PegPrimary, fpdecimal.Zero, fpdecimal.Zero, "", ""))
+
+	_, cancellation = ob.CancelOrder("bid")
+	if len(cancellation.Canceled) != 2 || cancellation.Canceled[0] != "bid" || cancellation.Canceled[1] != "peg-2" {
+		t.Fatal("Wrong cancellation", cancellation)
+	}
+}
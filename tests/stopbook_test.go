@@ -67,6 +67,40 @@ func TestStopBook_Activate(t *testing.T) {
 	}
 }
 
+func TestStopBook_ActivateCrossing(t *testing.T) {
+	stopBook := matchingo.NewStopBook()
+
+	stopBook.Append(matchingo.NewStopMarketOrder("order-103", matchingo.Buy, fpdecimal.FromInt(1), fpdecimal.FromInt(103), ""))
+	stopBook.Append(matchingo.NewStopMarketOrder("order-101.5-1", matchingo.Buy, fpdecimal.FromInt(1), fpdecimal.FromFloat(101.5), ""))
+	stopBook.Append(matchingo.NewStopMarketOrder("order-102", matchingo.Buy, fpdecimal.FromInt(1), fpdecimal.FromInt(102), ""))
+	stopBook.Append(matchingo.NewStopMarketOrder("order-101.5-2", matchingo.Buy, fpdecimal.FromInt(1), fpdecimal.FromFloat(101.5), ""))
+	stopBook.Append(matchingo.NewStopMarketOrder("order-sell-99", matchingo.Sell, fpdecimal.FromInt(1), fpdecimal.FromInt(99), ""))
+	stopBook.Append(matchingo.NewStopMarketOrder("order-sell-97", matchingo.Sell, fpdecimal.FromInt(1), fpdecimal.FromInt(97), ""))
+
+	slice := stopBook.Activate(fpdecimal.FromFloat(102.5))
+
+	expected := []string{"order-101.5-1", "order-101.5-2", "order-102"}
+	if len(slice) != len(expected) {
+		t.Fatal("invalid slice count", len(slice))
+	}
+
+	for i, id := range expected {
+		if slice[i].ID() != id {
+			t.Fatal("invalid activation order", i, slice[i].ID())
+		}
+	}
+
+	slice = stopBook.Activate(fpdecimal.FromInt(96))
+
+	if len(slice) != 2 || slice[0].ID() != "order-sell-99" || slice[1].ID() != "order-sell-97" {
+		t.Fatal("invalid activation order")
+	}
+
+	if stopBook.Len() != 1 || stopBook.String() == "" {
+		t.Fatal("invalid orders count")
+	}
+}
+
 func TestStopBook_Remove(t *testing.T) {
 	stopBook := matchingo.NewStopBook()
 
@@ -145,3 +179,60 @@ func TestStopBook_RemoveByID(t *testing.T) {
 		t.Fatal("invalid orders count")
 	}
 }
+
+func TestStopBook_Trail(t *testing.T) {
+	stopBook := matchingo.NewStopBook()
+
+	o1 := matchingo.NewTrailingStopLimitOrder(
+		"order-1",
+		matchingo.Sell,
+		fpdecimal.FromInt(10),
+		fpdecimal.FromInt(89),
+		fpdecimal.FromInt(90),
+		fpdecimal.FromInt(5),
+		matchingo.TrailAbsolute,
+		"",
+	)
+
+	o2 := matchingo.NewTrailingStopMarketOrder(
+		"order-2",
+		matchingo.Buy,
+		fpdecimal.FromInt(10),
+		fpdecimal.FromInt(120),
+		fpdecimal.FromInt(10),
+		matchingo.TrailPercent,
+		"",
+	)
+
+	stopBook.Append(o1)
+	stopBook.Append(o2)
+
+	if len(stopBook.Trail(fpdecimal.FromInt(100))) != 2 {
+		t.Fatal("invalid moved count")
+	}
+
+	if !o1.StopPrice().Equal(fpdecimal.FromInt(95)) || !o1.Price().Equal(fpdecimal.FromInt(94)) {
+		t.Fatal("invalid sell trailing stop", o1.StopPrice(), o1.Price())
+	}
+
+	if !o2.StopPrice().Equal(fpdecimal.FromInt(110)) {
+		t.Fatal("invalid buy trailing stop", o2.StopPrice())
+	}
+
+	// unfavourable move for both orders
+	if len(stopBook.Trail(fpdecimal.FromInt(100))) != 0 {
+		t.Fatal("invalid moved count")
+	}
+
+	if len(stopBook.Activate(fpdecimal.FromInt(96))) != 0 {
+		t.Fatal("trailing stop is not re-keyed")
+	}
+
+	if len(stopBook.Activate(fpdecimal.FromInt(95))) != 1 {
+		t.Fatal("trailing stop is not re-keyed")
+	}
+
+	if stopBook.Len() != 1 {
+		t.Fatal("invalid orders count")
+	}
+}